@@ -0,0 +1,316 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IPRangesURL is where Atlassian publishes the IP ranges of its cloud
+// products, including those Bitbucket delivers webhooks from.
+//
+// https://support.atlassian.com/organization-administration/docs/ip-addresses-and-domains-for-atlassian-cloud-products/
+const IPRangesURL = "https://ip-ranges.atlassian.com/"
+
+// bitbucketRanges are the ranges Bitbucket delivers webhooks from, as
+// published at the time of writing. Load the published ranges with
+// Allowlist.Refresh for the current list.
+var bitbucketRanges = []string{
+	"104.192.136.0/21",
+	"185.166.140.0/22",
+	"13.52.5.0/25",
+	"13.236.8.128/25",
+	"18.136.214.0/25",
+	"18.184.99.128/25",
+	"18.205.93.0/25",
+	"18.234.32.128/25",
+	"18.246.31.128/25",
+	"52.215.192.128/25",
+	"2401:1d80:3000::/36",
+}
+
+// DefaultAllowlist is used by IsValidSender. It initially holds the
+// ranges Bitbucket delivered webhooks from at the time of writing, and
+// can be kept up to date with Refresh or RefreshEvery.
+var DefaultAllowlist = mustAllowlist(bitbucketRanges...)
+
+// IPRangesFetcher gets a document in the format published at
+// IPRangesURL.
+type IPRangesFetcher func(ctx context.Context) ([]byte, error)
+
+// FetchIPRanges gets the ranges published at IPRangesURL using the
+// http client, or DefaultClient if nil.
+func FetchIPRanges(hc *http.Client) IPRangesFetcher {
+	if hc == nil {
+		hc = DefaultClient
+	}
+	return func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", IPRangesURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := hc.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching %s: %s", IPRangesURL, resp.Status)
+		}
+		return body, nil
+	}
+}
+
+// ReadIPRangesFile gets the ranges from a copy of the document published
+// at IPRangesURL saved to a file.
+func ReadIPRangesFile(path string) IPRangesFetcher {
+	return func(ctx context.Context) ([]byte, error) {
+		return os.ReadFile(path)
+	}
+}
+
+// ParseIPRanges parses a document in the format published at IPRangesURL,
+// returning the egress ranges of Bitbucket. Items that don't list their
+// products or directions are excluded, since they may belong to any
+// Atlassian product.
+func ParseIPRanges(data []byte) ([]netip.Prefix, error) {
+	doc := struct {
+		Items []struct {
+			CIDR      string   `json:"cidr"`
+			Product   []string `json:"product"`
+			Direction []string `json:"direction"`
+		} `json:"items"`
+	}{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	prefixes := []netip.Prefix{}
+	for _, item := range doc.Items {
+		if !contains(item.Product, "bitbucket") || !contains(item.Direction, "egress") {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(item.CIDR)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	if len(prefixes) == 0 {
+		return nil, errors.New("no Bitbucket IP ranges found")
+	}
+	return prefixes, nil
+}
+
+// Allowlist checks that webhook deliveries come from the IP ranges
+// Bitbucket delivers webhooks from. It is safe for concurrent use.
+type Allowlist struct {
+	mu       sync.RWMutex
+	prefixes []netip.Prefix
+	proxies  []netip.Prefix
+}
+
+// NewAllowlist creates an Allowlist of the given ranges, in CIDR
+// notation, ie "104.192.136.0/21". Single addresses are also accepted.
+func NewAllowlist(cidrs ...string) (*Allowlist, error) {
+	prefixes, err := parsePrefixes(cidrs)
+	if err != nil {
+		return nil, err
+	}
+	return &Allowlist{prefixes: prefixes}, nil
+}
+
+func mustAllowlist(cidrs ...string) *Allowlist {
+	a, err := NewAllowlist(cidrs...)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// TrustProxies sets the proxies, in CIDR notation, trusted to report the
+// address of the client they forwarded a request for in the
+// X-Forwarded-For header. See ClientIP.
+func (a *Allowlist) TrustProxies(cidrs ...string) error {
+	proxies, err := parsePrefixes(cidrs)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.proxies = proxies
+	a.mu.Unlock()
+	return nil
+}
+
+// Refresh replaces the ranges of the Allowlist with those fetched. The
+// ranges are left unchanged if they cannot be fetched or parsed.
+func (a *Allowlist) Refresh(ctx context.Context, fetch IPRangesFetcher) error {
+	data, err := fetch(ctx)
+	if err != nil {
+		return err
+	}
+	prefixes, err := ParseIPRanges(data)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.prefixes = prefixes
+	a.mu.Unlock()
+	return nil
+}
+
+// RefreshEvery refreshes the ranges of the Allowlist in the background,
+// once per interval, or once an hour if the interval is zero or less,
+// until the context is cancelled. Errors are passed to onError, if not
+// nil, and otherwise ignored. Call Refresh first if the ranges must be
+// current before RefreshEvery returns.
+func (a *Allowlist) RefreshEvery(ctx context.Context, interval time.Duration, fetch IPRangesFetcher, onError func(error)) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := a.Refresh(ctx, fetch); err != nil && onError != nil && ctx.Err() == nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// Allowed reports whether the IP address is in one of the ranges.
+func (a *Allowlist) Allowed(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return containsAddr(a.prefixes, addr.Unmap())
+}
+
+// ClientIP gets the address of the client that sent the request. If the
+// request came from a trusted proxy, this is the rightmost address in
+// the X-Forwarded-For header not of a trusted proxy; otherwise it is the
+// remote address of the request, since the header could be forged.
+func (a *Allowlist) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	remote, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if !containsAddr(a.proxies, remote.Unmap()) {
+		return remote.Unmap().String()
+	}
+
+	// walk the chain of proxies back from the one closest to us
+	var forwarded []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(v, ",")...)
+	}
+	client := remote.Unmap()
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap()
+		if !containsAddr(a.proxies, client) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// AllowedRequest reports whether the client that sent the request, as
+// found by ClientIP, is in one of the ranges.
+func (a *Allowlist) AllowedRequest(r *http.Request) bool {
+	return a.Allowed(a.ClientIP(r))
+}
+
+// Require returns a handler that rejects requests from clients outside
+// the ranges with 403 Forbidden, and passes the rest to h.
+func (a *Allowlist) Require(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.AllowedRequest(r) {
+			http.Error(w, "sender not allowed", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Check's to see if the Post-Receive Build Hook is coming
+// from a valid sender (IP Address), using the DefaultAllowlist.
+func IsValidSender(ip string) bool {
+	return DefaultAllowlist.Allowed(ip)
+}
+
+// parsePrefixes parses ranges in CIDR notation, or single addresses.
+func parsePrefixes(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := []netip.Prefix{}
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
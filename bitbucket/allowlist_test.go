@@ -0,0 +1,244 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_AllowlistRefresh(t *testing.T) {
+	a, err := NewAllowlist("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetch := func(ctx context.Context) ([]byte, error) {
+		return []byte(sampleIPRanges), nil
+	}
+	if err := a.Refresh(context.Background(), fetch); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]bool{
+		"104.192.136.7":     true,
+		"2401:1d80:3004::9": true,
+		"10.1.2.3":          false, // replaced by the refresh
+		"52.1.1.1":          false, // jira
+		"34.1.1.1":          false, // ingress only
+		"35.1.1.1":          false, // untagged
+	}
+	for ip, want := range tests {
+		if a.Allowed(ip) != want {
+			t.Errorf("expected IP address [%v] validation [%v]", ip, want)
+		}
+	}
+
+	// a failed refresh should keep the existing ranges
+	fail := func(ctx context.Context) ([]byte, error) {
+		return nil, errors.New("unavailable")
+	}
+	if err := a.Refresh(context.Background(), fail); err == nil {
+		t.Errorf("expected the refresh to fail")
+	}
+	if !a.Allowed("104.192.136.7") {
+		t.Errorf("expected the ranges to be kept after a failed refresh")
+	}
+}
+
+func Test_AllowlistClientIP(t *testing.T) {
+	a, err := NewAllowlist("104.192.136.0/21")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.TrustProxies("10.0.0.0/8", "fd00::/8"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		remote    string
+		forwarded []string
+		want      string
+	}{
+		// not from a proxy, so the header could be forged
+		{"203.0.113.9:1234", []string{"104.192.136.7"}, "203.0.113.9"},
+		// from a proxy
+		{"10.0.0.1:1234", []string{"104.192.136.7"}, "104.192.136.7"},
+		// through several proxies, with a forged address on the left
+		{"10.0.0.1:1234", []string{"104.192.136.7, 203.0.113.9", "10.0.0.2"}, "203.0.113.9"},
+		// over IPv6
+		{"[fd00::1]:1234", []string{"2001:db8::1"}, "2001:db8::1"},
+		// from a proxy that didn't set the header
+		{"10.0.0.1:1234", nil, "10.0.0.1"},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/hook", nil)
+		r.RemoteAddr = test.remote
+		for _, v := range test.forwarded {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		if ip := a.ClientIP(r); ip != test.want {
+			t.Errorf("remote [%v] forwarded %v client [%v]; want [%v]", test.remote, test.forwarded, ip, test.want)
+		}
+	}
+
+	h := a.Require(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	r := httptest.NewRequest("POST", "/hook", nil)
+	r.RemoteAddr = "203.0.113.9:1234"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status [%v]; want [%v]", w.Code, http.StatusForbidden)
+	}
+}
+
+var sampleIPRanges = `
+{
+    "creationDate": "2024-03-01T00:00:00.000000",
+    "syncToken": 1709251200,
+    "items": [
+        {"network": "104.192.136.0", "mask_len": 21, "cidr": "104.192.136.0/21", "mask": "255.255.248.0",
+         "region": ["global"], "product": ["bitbucket", "confluence", "jira"], "direction": ["egress", "ingress"]},
+        {"network": "2401:1d80:3000::", "mask_len": 36, "cidr": "2401:1d80:3000::/36",
+         "region": ["global"], "product": ["bitbucket"], "direction": ["egress"]},
+        {"network": "52.0.0.0", "mask_len": 8, "cidr": "52.0.0.0/8",
+         "region": ["us-east-1"], "product": ["jira"], "direction": ["egress"]},
+        {"network": "34.0.0.0", "mask_len": 8, "cidr": "34.0.0.0/8",
+         "region": ["us-east-1"], "product": ["bitbucket"], "direction": ["ingress"]},
+        {"network": "35.0.0.0", "mask_len": 8, "cidr": "35.0.0.0/8", "region": ["us-east-1"]}
+    ]
+}
+`
+
+func Test_AllowlistRefreshEvery(t *testing.T) {
+	a, err := NewAllowlist("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first fetch fails, and the rest succeed
+	var mu sync.Mutex
+	fetches := 0
+	fetched := make(chan struct{}, 10)
+	fetch := func(ctx context.Context) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		defer func() { fetched <- struct{}{} }()
+		if fetches == 1 {
+			return nil, errors.New("unavailable")
+		}
+		return []byte(sampleIPRanges), nil
+	}
+	errs := make(chan error, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.RefreshEvery(ctx, 5*time.Millisecond, fetch, func(err error) { errs <- err })
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-fetched:
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for fetch [%v]", i+1)
+		}
+	}
+	// the ranges are replaced just after the fetch returns
+	deadline := time.Now().Add(time.Second)
+	for !a.Allowed("104.192.136.7") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !a.Allowed("104.192.136.7") || a.Allowed("10.1.2.3") {
+		t.Errorf("expected the ranges to be refreshed")
+	}
+	if len(errs) != 1 {
+		t.Errorf("reported %v errors; want 1", len(errs))
+	}
+
+	// no more fetches once the context is cancelled
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	stopped := fetches
+	mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if fetches != stopped {
+		t.Errorf("fetched [%v] times after cancelling; want none", fetches-stopped)
+	}
+}
+
+func Test_AllowlistRefreshEveryZero(t *testing.T) {
+	a, err := NewAllowlist("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetch := func(ctx context.Context) ([]byte, error) {
+		t.Errorf("expected no fetch before the default interval")
+		return nil, nil
+	}
+
+	// a zero interval falls back to the default, rather than panicking
+	// in the background
+	ctx, cancel := context.WithCancel(context.Background())
+	a.RefreshEvery(ctx, 0, fetch, nil)
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+}
+
+func Test_AllowlistReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ip-ranges.json")
+	if err := os.WriteFile(path, []byte(sampleIPRanges), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewAllowlist()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Refresh(context.Background(), ReadIPRangesFile(path)); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Allowed("2401:1d80:3004::9") {
+		t.Errorf("expected the ranges to be loaded from the file")
+	}
+
+	// a missing file leaves the ranges unchanged
+	if err := a.Refresh(context.Background(), ReadIPRangesFile(path+".missing")); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+	if !a.Allowed("2401:1d80:3004::9") {
+		t.Errorf("expected the ranges to be kept after a failed refresh")
+	}
+}
+
+func Test_FetchIPRanges(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleIPRanges))
+	}))
+	defer srv.Close()
+
+	// redirect the published URL to the test server
+	hc := &http.Client{Transport: RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme, r.URL.Host = "http", srv.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+
+	fetch := FetchIPRanges(hc)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if data, err := fetch(context.Background()); err != nil || len(data) == 0 {
+				t.Errorf("fetch [%d bytes] error [%v]", len(data), err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/webdevwilson/go-bitbucket/oauth1"
@@ -8,7 +9,7 @@ import (
 
 // Auth represents an authentication strategy
 type Auth interface {
-	authenticate(r *http.Request) error
+	authenticate(ctx context.Context, r *http.Request) error
 }
 
 // OAuth for doing OAuth authentication
@@ -19,7 +20,12 @@ type OAuth struct {
 	TokenSecret    string
 }
 
-func (auth *OAuth) authenticate(r *http.Request) error {
+func (auth *OAuth) authenticate(ctx context.Context, r *http.Request) error {
+	// don't bother signing a request that has already been cancelled
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	client := oauth1.Consumer{
 		ConsumerKey:    auth.ConsumerKey,
 		ConsumerSecret: auth.ConsumerSecret,
@@ -41,7 +47,7 @@ type BasicAuth struct {
 }
 
 // authenticate adds BASIC Auth header
-func (auth *BasicAuth) authenticate(r *http.Request) error {
+func (auth *BasicAuth) authenticate(ctx context.Context, r *http.Request) error {
 	r.SetBasicAuth(auth.Username, auth.Password)
 	return nil
 }
@@ -49,6 +55,6 @@ func (auth *BasicAuth) authenticate(r *http.Request) error {
 // Anonymous requests do no authentication
 type Anonymous struct{}
 
-func (auth *Anonymous) authenticate(r *http.Request) error {
+func (auth *Anonymous) authenticate(ctx context.Context, r *http.Request) error {
 	return nil
 }
@@ -2,15 +2,28 @@ package bitbucket
 
 import (
 	"errors"
+	"net/http"
 )
 
 var (
 	ErrNilClient = errors.New("client is nil")
 )
 
-// New creates an instance of the Bitbucket Client
-func New(auth Auth) *Client {
-	c := &Client{auth: auth}
+// New creates an instance of the Bitbucket Client, applying
+// any Options to it.
+func New(auth Auth, opts ...Option) *Client {
+	c := &Client{
+		auth:    auth,
+		baseURL: DefaultBaseURL,
+		header:  make(http.Header),
+		logger:  nopLogger{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if len(c.middleware) != 0 {
+		c.httpClient = chain(c.client(), c.middleware)
+	}
 
 	c.Keys = &KeyResource{c}
 	c.Repos = &RepoResource{c}
@@ -21,6 +34,14 @@ func New(auth Auth) *Client {
 	c.RepoKeys = &RepoKeyResource{c}
 	c.Sources = &SourceResource{c}
 	c.Groups = &GroupResource{c}
+	c.PullRequests = &PullRequestResource{c}
+	c.Commits = &CommitResource{c}
+	c.Statuses = &StatusResource{c}
+	c.Reports = &ReportResource{c}
+	c.Refs = &RefResource{c}
+	c.BranchRestrictions = &BranchRestrictionResource{c}
+	c.DefaultReviewers = &DefaultReviewerResource{c}
+	c.Webhooks = &WebhookResource{c}
 	return c
 }
 
@@ -28,6 +49,15 @@ func New(auth Auth) *Client {
 type Client struct {
 	auth Auth
 
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	header     http.Header
+	retry      *RetryPolicy
+	limiter    *RateLimiter
+	middleware []Middleware
+	logger     Logger
+
 	Repos    *RepoResource
 	Users    *UserResource
 	Emails   *EmailResource
@@ -37,6 +67,15 @@ type Client struct {
 	Sources  *SourceResource
 	RepoKeys *RepoKeyResource
 	Groups   *GroupResource
+
+	PullRequests       *PullRequestResource
+	Commits            *CommitResource
+	Statuses           *StatusResource
+	Reports            *ReportResource
+	Refs               *RefResource
+	BranchRestrictions *BranchRestrictionResource
+	DefaultReviewers   *DefaultReviewerResource
+	Webhooks           *WebhookResource
 }
 
 // Guest Client that can be used to access
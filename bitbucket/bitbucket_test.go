@@ -1,8 +1,13 @@
 package bitbucket
 
 import (
+	"encoding/json"
 	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"testing"
 )
 
 // Instance of the Bitbucket client that we'll use for our unit tests
@@ -75,3 +80,29 @@ func init() {
 	}
 	client = New(auth)
 }
+
+// newTestServer creates a Client pointed at an httptest.Server that
+// serves the handler.
+func newTestServer(t *testing.T, handler http.HandlerFunc) (*Client, *httptest.Server) {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return New(&Anonymous{}, WithBaseURL(srv.URL), WithHTTPClient(srv.Client())), srv
+}
+
+// decodeBody unmarshals the JSON body of a request. It is called from
+// the handler's goroutine, so it can't stop the test; an invalid body is
+// reported, and an empty body returned.
+func decodeBody(t *testing.T, r *http.Request) map[string]interface{} {
+	body := map[string]interface{}{}
+	raw, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		t.Errorf("reading request body: %v", err)
+		return body
+	}
+	if len(raw) != 0 {
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("invalid request body %s: %v", raw, err)
+		}
+	}
+	return body
+}
@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -45,26 +46,29 @@ type BrokerField struct {
 // on this resource require the caller to authenticate.
 //
 // https://confluence.atlassian.com/display/BITBUCKET/services+Resource
+//
+// Deprecated: Bitbucket no longer supports services; use WebhookResource
+// instead.
 type BrokerResource struct {
 	client *Client
 }
 
-func (r *BrokerResource) List(owner, slug string) ([]*Broker, error) {
+func (r *BrokerResource) List(ctx context.Context, owner, slug string) ([]*Broker, error) {
 	brokers := []*Broker{}
 	path := fmt.Sprintf("/repositories/%s/%s/services", owner, slug)
 
-	if err := r.client.do("GET", path, nil, nil, &brokers); err != nil {
+	if err := r.client.do(ctx, "GET", path, nil, nil, &brokers); err != nil {
 		return nil, err
 	}
 
 	return brokers, nil
 }
 
-func (r *BrokerResource) Find(owner, slug string, id int) (*Broker, error) {
+func (r *BrokerResource) Find(ctx context.Context, owner, slug string, id int) (*Broker, error) {
 	brokers := []*Broker{}
 	path := fmt.Sprintf("/repositories/%s/%s/services/%v", owner, slug, id)
 
-	if err := r.client.do("GET", path, nil, nil, &brokers); err != nil {
+	if err := r.client.do(ctx, "GET", path, nil, nil, &brokers); err != nil {
 		return nil, err
 	}
 
@@ -75,8 +79,8 @@ func (r *BrokerResource) Find(owner, slug string, id int) (*Broker, error) {
 	return brokers[0], nil
 }
 
-func (r *BrokerResource) FindUrl(owner, slug, link, brokerType string) (*Broker, error) {
-	brokers, err := r.List(owner, slug)
+func (r *BrokerResource) FindUrl(ctx context.Context, owner, slug, link, brokerType string) (*Broker, error) {
+	brokers, err := r.List(ctx, owner, slug)
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +110,7 @@ func (r *BrokerResource) FindUrl(owner, slug, link, brokerType string) (*Broker,
 	return nil, ErrNotFound
 }
 
-func (r *BrokerResource) Create(owner, slug, link, brokerType string) (*Broker, error) {
+func (r *BrokerResource) Create(ctx context.Context, owner, slug, link, brokerType string) (*Broker, error) {
 	values := url.Values{}
 	values.Add("type", brokerType)
 	values.Add("URL", link)
@@ -123,14 +127,14 @@ func (r *BrokerResource) Create(owner, slug, link, brokerType string) (*Broker,
 
 	b := Broker{}
 	path := fmt.Sprintf("/repositories/%s/%s/services", owner, slug)
-	if err := r.client.do("POST", path, nil, values, &b); err != nil {
+	if err := r.client.do(ctx, "POST", path, nil, values, &b); err != nil {
 		return nil, err
 	}
 
 	return &b, nil
 }
 
-func (r *BrokerResource) Update(owner, slug, link, brokerType string, id int) (*Broker, error) {
+func (r *BrokerResource) Update(ctx context.Context, owner, slug, link, brokerType string, id int) (*Broker, error) {
 	values := url.Values{}
 	values.Add("type", brokerType)
 	values.Add("URL", link)
@@ -142,7 +146,7 @@ func (r *BrokerResource) Update(owner, slug, link, brokerType string, id int) (*
 
 	b := Broker{}
 	path := fmt.Sprintf("/repositories/%s/%s/services/%v", owner, slug, id)
-	if err := r.client.do("PUT", path, nil, values, &b); err != nil {
+	if err := r.client.do(ctx, "PUT", path, nil, values, &b); err != nil {
 		return nil, err
 	}
 
@@ -151,53 +155,36 @@ func (r *BrokerResource) Update(owner, slug, link, brokerType string, id int) (*
 
 // CreateUpdate will attempt to Create a Broker (Server Hook) if
 // it doesn't already exist in the Bitbucket.
-func (r *BrokerResource) CreateUpdate(owner, slug, link, brokerType string) (*Broker, error) {
-	if found, err := r.FindUrl(owner, slug, link, brokerType); err == nil {
+func (r *BrokerResource) CreateUpdate(ctx context.Context, owner, slug, link, brokerType string) (*Broker, error) {
+	if found, err := r.FindUrl(ctx, owner, slug, link, brokerType); err == nil {
 		// if the Broker already exists, just return it
 		// ... not need to re-create
 		//fmt.Println("Broker already found, skipping!", brokerType)
 		return found, nil
 	}
 
-	return r.Create(owner, slug, link, brokerType)
+	return r.Create(ctx, owner, slug, link, brokerType)
 }
 
-func (r *BrokerResource) Delete(owner, slug string, id int) error {
+func (r *BrokerResource) Delete(ctx context.Context, owner, slug string, id int) error {
 	path := fmt.Sprintf("/repositories/%s/%s/services/%v", owner, slug, id)
-	return r.client.do("DELETE", path, nil, nil, nil)
+	return r.client.do(ctx, "DELETE", path, nil, nil, nil)
 }
 
-func (r *BrokerResource) DeleteUrl(owner, slug, url, brokerType string) error {
-	broker, err := r.FindUrl(owner, slug, url, brokerType)
+func (r *BrokerResource) DeleteUrl(ctx context.Context, owner, slug, url, brokerType string) error {
+	broker, err := r.FindUrl(ctx, owner, slug, url, brokerType)
 	if err != nil {
 		return err
 	}
 
-	return r.Delete(owner, slug, broker.Id)
+	return r.Delete(ctx, owner, slug, broker.Id)
 }
 
-// patch := bitbucket.GetPatch(repo, p.Id, u.BitbucketToken, u.BitbucketSecret)
-func (r *BrokerResource) GetPatch(owner, slug string, id int) (string, error) {
-	data := []byte{}
-	// uri, err := url.Parse("https://api.bitbucket.org/1.0" + path)
-	// https://bitbucket.org/!api/2.0/repositories/tdburke/test_mymysql/pullrequests/1/patch
-
-	path := fmt.Sprintf("/repositories/tdburke/test_mymysql/pullrequests/1/patch")
-
-	fmt.Println(path)
-
-	if err := r.client.do("GET", path, nil, nil, &data); err != nil {
-		fmt.Println("Get error:", err)
-		return "", err
-	}
-
-	fmt.Println(data)
-
-	if len(data) == 0 {
-		return "", ErrNotFound
-	}
-
-	return "", nil
+// GetPatch gets the patch of a pull request.
+//
+// Deprecated: use PullRequestResource.Patch or PatchReader instead.
+func (r *BrokerResource) GetPatch(ctx context.Context, owner, slug string, id int) (string, error) {
+	return r.client.PullRequests.Patch(ctx, owner, slug, id)
 }
 
 // -----------------------------------------------------------------------------
@@ -265,7 +252,6 @@ func ParsePullRequestHook(raw []byte) (*PullRequestHook, error) {
 	hook := PullRequestHook{}
 
 	if p, ok := data["pullrequest_created"]; ok {
-		fmt.Println(p["id"])
 		hook.Id = fmt.Sprintf("%v", p["id"])
 		if hook.Id == "" {
 			return nil, errors.New("Could not parse bitbucket pullrequest_created message")
@@ -275,7 +261,7 @@ func ParsePullRequestHook(raw []byte) (*PullRequestHook, error) {
 			brokers := []*Broker{}
 			path := fmt.Sprintf("/repositories/%s/%s/services/%v", owner, slug, id)
 
-			if err := r.client.do("GET", path, nil, nil, &brokers); err != nil {
+			if err := r.client.do(ctx, "GET", path, nil, nil, &brokers); err != nil {
 				return nil, err
 			}
 
@@ -290,13 +276,3 @@ func ParsePullRequestHook(raw []byte) (*PullRequestHook, error) {
 	// How do we get the diff file?
 	return nil, errors.New("Could not parse bitbucket pull request hook")
 }
-
-var ips = map[string]bool{
-	"63.246.22.222": true,
-}
-
-// Check's to see if the Post-Receive Build Hook is coming
-// from a valid sender (IP Address)
-func IsValidSender(ip string) bool {
-	return ips[ip]
-}
@@ -79,10 +79,13 @@ func Test_PostReceiveHooks(t *testing.T) {
 
 func Test_IsValidSender(t *testing.T) {
 	str := map[string]bool{
-		"63.246.22.222": true,
-		"127.0.0.1":     false,
-		"localhost":     false,
-		"1.2.3.4":       false,
+		"104.192.143.1":        true,
+		"::ffff:104.192.143.1": true,
+		"2401:1d80:3000::1":    true,
+		"63.246.22.222":        false,
+		"127.0.0.1":            false,
+		"localhost":            false,
+		"1.2.3.4":              false,
 	}
 
 	for k, v := range str {
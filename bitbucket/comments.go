@@ -0,0 +1,189 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Content is rich text, such as the body of a comment.
+type Content struct {
+	// The text as entered by the user, usually Markdown.
+	Raw string `json:"raw"`
+
+	// The markup language of Raw, ie "markdown".
+	Markup string `json:"markup,omitempty"`
+
+	// The text rendered as HTML.
+	HTML string `json:"html,omitempty"`
+}
+
+type Comment struct {
+	Id      int      `json:"id"`
+	Content *Content `json:"content"`
+	User    *User    `json:"user"`
+
+	// Set for inline comments, which are anchored to a line
+	// in the diff.
+	Inline *Inline `json:"inline,omitempty"`
+
+	// The comment this is a reply to. Only the Id is populated.
+	Parent *Comment `json:"parent,omitempty"`
+
+	Deleted    bool               `json:"deleted"`
+	Pending    bool               `json:"pending"`
+	Resolution *CommentResolution `json:"resolution,omitempty"`
+
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+	Links     *Links    `json:"links,omitempty"`
+
+	// The replies to this comment. These are not returned by Bitbucket,
+	// but are populated by CommentThreads.
+	Replies []*Comment `json:"-"`
+}
+
+// Inline anchors a comment to a file, and optionally a line, in a diff.
+// A comment on an added line sets To, a comment on a removed line sets
+// From, and a comment on the file as a whole sets neither.
+type Inline struct {
+	Path string `json:"path"`
+
+	// The line in the old version of the file.
+	From *int `json:"from,omitempty"`
+
+	// The line in the new version of the file.
+	To *int `json:"to,omitempty"`
+}
+
+// CommentResolution records who resolved a comment thread, and when.
+type CommentResolution struct {
+	Type      string    `json:"type"`
+	User      *User     `json:"user"`
+	CreatedOn time.Time `json:"created_on"`
+}
+
+// CommentOptions describes a comment to create.
+type CommentOptions struct {
+	// The body of the comment, in Markdown.
+	Raw string
+
+	// Anchors the comment to a line in the diff.
+	Inline *Inline
+
+	// The id of the comment to reply to, if any.
+	ParentId int
+}
+
+// body converts the options to the JSON structure expected
+// by Bitbucket.
+func (o *CommentOptions) body() map[string]interface{} {
+	body := map[string]interface{}{}
+	if o == nil {
+		return body
+	}
+	body["content"] = map[string]string{"raw": o.Raw}
+	if o.Inline != nil {
+		body["inline"] = o.Inline
+	}
+	if o.ParentId != 0 {
+		body["parent"] = map[string]int{"id": o.ParentId}
+	}
+	return body
+}
+
+// CommentThreads arranges a flat list of comments into threads, returning
+// the top level comments with their Replies populated. Comments are kept
+// in the order given, and replies whose parent is not in the list are
+// treated as top level comments.
+func CommentThreads(comments []*Comment) []*Comment {
+	byId := map[int]*Comment{}
+	for _, c := range comments {
+		c.Replies = nil
+		byId[c.Id] = c
+	}
+
+	threads := []*Comment{}
+	for _, c := range comments {
+		if c.Parent != nil {
+			if parent, ok := byId[c.Parent.Id]; ok {
+				parent.Replies = append(parent.Replies, c)
+				continue
+			}
+		}
+		threads = append(threads, c)
+	}
+
+	return threads
+}
+
+// Gets the comments on a pull request, including inline comments
+// and replies. Use CommentThreads to arrange them into threads.
+func (r *PullRequestResource) ListComments(ctx context.Context, owner, slug string, id int, opts *ListOptions) *Iterator[*Comment] {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/comments", owner, slug, id)
+	return newIterator[*Comment](ctx, r.client, path, nil, opts)
+}
+
+// Gets a comment on a pull request.
+func (r *PullRequestResource) FindComment(ctx context.Context, owner, slug string, id, commentId int) (*Comment, error) {
+	comment := Comment{}
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/comments/%v", owner, slug, id, commentId)
+
+	if err := r.client.do2(ctx, "GET", path, nil, nil, &comment); err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// Creates a comment on a pull request, which may be inline or a
+// reply to another comment.
+func (r *PullRequestResource) CreateComment(ctx context.Context, owner, slug string, id int, opts *CommentOptions) (*Comment, error) {
+	comment := Comment{}
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/comments", owner, slug, id)
+
+	if err := r.client.do2(ctx, "POST", path, nil, opts.body(), &comment); err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// Updates the body of a comment on a pull request.
+func (r *PullRequestResource) UpdateComment(ctx context.Context, owner, slug string, id, commentId int, raw string) (*Comment, error) {
+	body := map[string]interface{}{
+		"content": map[string]string{"raw": raw},
+	}
+
+	comment := Comment{}
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/comments/%v", owner, slug, id, commentId)
+	if err := r.client.do2(ctx, "PUT", path, nil, body, &comment); err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// Deletes a comment on a pull request.
+func (r *PullRequestResource) DeleteComment(ctx context.Context, owner, slug string, id, commentId int) error {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/comments/%v", owner, slug, id, commentId)
+	return r.client.do2(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// Resolves the thread started by a comment on a pull request.
+func (r *PullRequestResource) ResolveComment(ctx context.Context, owner, slug string, id, commentId int) (*CommentResolution, error) {
+	resolution := CommentResolution{}
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/comments/%v/resolve", owner, slug, id, commentId)
+
+	if err := r.client.do2(ctx, "POST", path, nil, nil, &resolution); err != nil {
+		return nil, err
+	}
+
+	return &resolution, nil
+}
+
+// Reopens a resolved comment thread on a pull request.
+func (r *PullRequestResource) ReopenComment(ctx context.Context, owner, slug string, id, commentId int) error {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/comments/%v/resolve", owner, slug, id, commentId)
+	return r.client.do2(ctx, "DELETE", path, nil, nil, nil)
+}
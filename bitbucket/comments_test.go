@@ -0,0 +1,127 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func Test_Comments(t *testing.T) {
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		const path = "/2.0/repositories/marcus/project-x/pullrequests/7/comments"
+		switch {
+		case r.Method == "GET" && r.URL.Path == path:
+			w.Write([]byte(sampleComments))
+		case r.Method == "POST" && r.URL.Path == path:
+			body := decodeBody(t, r)
+			inline, ok := body["inline"].(map[string]interface{})
+			if !ok || inline["path"] != "somefile.py" || inline["to"] != 2.0 {
+				t.Errorf("inline [%v]; want path [%v] to [%v]", inline, "somefile.py", 2)
+			}
+			if _, ok := inline["from"]; ok {
+				t.Errorf("expected no from line; got %v", inline["from"])
+			}
+			if parent, ok := body["parent"].(map[string]interface{}); !ok || parent["id"] != 1.0 {
+				t.Errorf("parent [%v]; want [%v]", body["parent"], 1)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 4, "content": {"raw": "nit"}}`))
+		case r.Method == "POST" && r.URL.Path == path+"/1/resolve":
+			w.Write([]byte(`{"type": "comment_resolution", "user": {"uuid": "{a1b2}"}}`))
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request [%v %v]", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ctx := context.Background()
+
+	// LIST and thread the comments
+	comments, err := c.PullRequests.ListComments(ctx, "marcus", "project-x", 7, nil).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	threads := CommentThreads(comments)
+	if len(threads) != 2 {
+		t.Fatalf("thread count [%v]; want [%v]", len(threads), 2)
+	}
+	if len(threads[0].Replies) != 1 || threads[0].Replies[0].Id != 2 {
+		t.Errorf("expected comment 2 to reply to comment 1; got %v", threads[0].Replies)
+	}
+	if threads[1].Inline == nil || threads[1].Inline.Path != "somefile.py" || *threads[1].Inline.To != 2 {
+		t.Errorf("expected comment 3 to be inline on somefile.py:2; got %v", threads[1].Inline)
+	}
+	if threads[0].Content.HTML != "<p>Looks <strong>good</strong></p>" {
+		t.Errorf("html [%v]", threads[0].Content.HTML)
+	}
+
+	// CREATE an inline reply
+	line := 2
+	comment, err := c.PullRequests.CreateComment(ctx, "marcus", "project-x", 7, &CommentOptions{
+		Raw:      "nit",
+		Inline:   &Inline{Path: "somefile.py", To: &line},
+		ParentId: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comment.Id != 4 {
+		t.Errorf("comment id [%v]; want [%v]", comment.Id, 4)
+	}
+
+	// RESOLVE, REOPEN and DELETE
+	if res, err := c.PullRequests.ResolveComment(ctx, "marcus", "project-x", 7, 1); err != nil || res.User.UUID != "{a1b2}" {
+		t.Errorf("expected resolution by {a1b2}; got %v %v", res, err)
+	}
+	if err := c.PullRequests.ReopenComment(ctx, "marcus", "project-x", 7, 1); err != nil {
+		t.Error(err)
+	}
+	if err := c.PullRequests.DeleteComment(ctx, "marcus", "project-x", 7, 4); err != nil {
+		t.Error(err)
+	}
+}
+
+var sampleComments = `
+{
+    "pagelen": 100,
+    "values": [
+        {
+            "id": 1,
+            "content": {"raw": "Looks **good**", "markup": "markdown", "html": "<p>Looks <strong>good</strong></p>"},
+            "user": {"uuid": "{c3d4}", "display_name": "Jane"},
+            "deleted": false,
+            "created_on": "2012-05-30T06:00:00.000000+00:00"
+        },
+        {
+            "id": 2,
+            "content": {"raw": "Thanks!"},
+            "user": {"uuid": "{a1b2}", "display_name": "Marcus Bertrand"},
+            "parent": {"id": 1},
+            "created_on": "2012-05-30T06:05:00.000000+00:00"
+        },
+        {
+            "id": 3,
+            "content": {"raw": "typo"},
+            "user": {"uuid": "{c3d4}", "display_name": "Jane"},
+            "inline": {"path": "somefile.py", "from": null, "to": 2},
+            "created_on": "2012-05-30T06:10:00.000000+00:00"
+        }
+    ]
+}
+`
+
+func Test_CommentsNilOptions(t *testing.T) {
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if body := decodeBody(t, r); len(body) != 0 {
+			t.Errorf("body [%v]; want an empty body", body)
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type": "error", "error": {"message": "content: This field is required."}}`))
+	})
+
+	// Bitbucket rejects the comment, rather than the client panicking
+	if _, err := c.PullRequests.CreateComment(context.Background(), "marcus", "project-x", 7, nil); err == nil {
+		t.Errorf("expected an error")
+	}
+}
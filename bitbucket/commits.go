@@ -0,0 +1,113 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// RepoCommit is a commit in a repository, as returned by the 2.0 API.
+// (Commit is the commit of a legacy POST hook.)
+type RepoCommit struct {
+	Hash    string        `json:"hash"`
+	Date    time.Time     `json:"date"`
+	Message string        `json:"message"`
+	Summary *Content      `json:"summary,omitempty"`
+	Author  *CommitAuthor `json:"author"`
+
+	// The parents of the commit. A merge commit has more than one.
+	Parents []*CommitRef `json:"parents"`
+
+	Repository *RepoRef `json:"repository,omitempty"`
+	Links      *Links   `json:"links,omitempty"`
+}
+
+// CommitAuthor is the author of a commit. User is nil if the author
+// could not be matched to a Bitbucket account.
+type CommitAuthor struct {
+	// The author as recorded in the commit, ie "Marcus <marcus@example.com>".
+	Raw  string `json:"raw"`
+	User *User  `json:"user,omitempty"`
+}
+
+// CommitListOptions filters and paginates a listing of commits.
+type CommitListOptions struct {
+	ListOptions
+
+	// Only list commits reachable from these revisions (ie branches,
+	// tags or hashes). All branches are included if empty.
+	Include []string
+
+	// Don't list commits reachable from these revisions. To list the
+	// commits between two revisions, include the newer one and exclude
+	// the older one.
+	Exclude []string
+
+	// Only list commits that modify this path.
+	Path string
+}
+
+// Use the commits resource to browse the history of a repository.
+// This resource uses the 2.0 API, and is read-only.
+//
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-commits/
+type CommitResource struct {
+	client *Client
+}
+
+// Gets the commits of a repository, newest first.
+func (r *CommitResource) List(ctx context.Context, owner, slug string, opts *CommitListOptions) *Iterator[*RepoCommit] {
+	params := url.Values{}
+	var listOpts *ListOptions
+	if opts != nil {
+		for _, rev := range opts.Include {
+			params.Add("include", rev)
+		}
+		for _, rev := range opts.Exclude {
+			params.Add("exclude", rev)
+		}
+		if len(opts.Path) != 0 {
+			params.Set("path", opts.Path)
+		}
+		listOpts = &opts.ListOptions
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/commits", owner, slug)
+	return newIterator[*RepoCommit](ctx, r.client, path, params, listOpts)
+}
+
+// Gets the commit at the given revision.
+func (r *CommitResource) Find(ctx context.Context, owner, slug, revision string) (*RepoCommit, error) {
+	commit := RepoCommit{}
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s", owner, slug, revision)
+
+	if err := r.client.do2(ctx, "GET", path, nil, nil, &commit); err != nil {
+		return nil, err
+	}
+
+	return &commit, nil
+}
+
+// Gets a unified diff. The spec is either a single revision, in which
+// case the diff is against its first parent, or two revisions separated
+// by "..", in which case the diff is of the first revision relative to
+// the second (ie "feature..master"). The caller must close the returned
+// reader.
+func (r *CommitResource) DiffReader(ctx context.Context, owner, slug, spec string) (io.ReadCloser, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/diff/%s", owner, slug, spec)
+	return r.client.stream2(ctx, path, nil)
+}
+
+// Gets a unified diff. See DiffReader for the format of the spec.
+func (r *CommitResource) Diff(ctx context.Context, owner, slug, spec string) (string, error) {
+	return readString(r.DiffReader(ctx, owner, slug, spec))
+}
+
+// Gets the lines added and removed for each file changed. See
+// DiffReader for the format of the spec.
+func (r *CommitResource) Diffstat(ctx context.Context, owner, slug, spec string, opts *ListOptions) *Iterator[*DiffStat] {
+	path := fmt.Sprintf("/repositories/%s/%s/diffstat/%s", owner, slug, spec)
+	return newIterator[*DiffStat](ctx, r.client, path, nil, opts)
+}
@@ -0,0 +1,85 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func Test_Commits(t *testing.T) {
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2.0/repositories/marcus/project-x/commits":
+			q := r.URL.Query()
+			if q.Get("include") != "feature" || q.Get("exclude") != "master" || q.Get("path") != "somefile.py" {
+				t.Errorf("query [%v]; want include=feature exclude=master path=somefile.py", q)
+			}
+			w.Write([]byte(`{"pagelen": 30, "values": [` + sampleCommit + `]}`))
+		case "/2.0/repositories/marcus/project-x/commit/620ade18607a":
+			w.Write([]byte(sampleCommit))
+		case "/2.0/repositories/marcus/project-x/diff/feature..master":
+			w.Write([]byte(sampleDiff))
+		case "/2.0/repositories/marcus/project-x/diffstat/feature..master":
+			w.Write([]byte(sampleDiffStat))
+		default:
+			t.Errorf("unexpected request [%v %v]", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ctx := context.Background()
+
+	// LIST the commits between two branches
+	opts := &CommitListOptions{Include: []string{"feature"}, Exclude: []string{"master"}, Path: "somefile.py"}
+	commits, err := c.Commits.List(ctx, "marcus", "project-x", opts).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 {
+		t.Fatalf("commit count [%v]; want [%v]", len(commits), 1)
+	}
+
+	// FIND a single commit
+	commit, err := c.Commits.Find(ctx, "marcus", "project-x", "620ade18607a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.Hash != "620ade18607ac42d872b568bb92acaa9a28620e9" {
+		t.Errorf("hash [%v]", commit.Hash)
+	}
+	if commit.Author.Raw != "Marcus Bertrand <marcus@somedomain.com>" || commit.Author.User.Nickname != "marcus" {
+		t.Errorf("author [%v] [%v]", commit.Author.Raw, commit.Author.User)
+	}
+	if len(commit.Parents) != 1 || commit.Parents[0].Hash != "702c70160afc" {
+		t.Errorf("parents [%v]; want [%v]", commit.Parents, "702c70160afc")
+	}
+	if commit.Date.Year() != 2012 || commit.Message != "Added some more things to somefile.py\n" {
+		t.Errorf("date [%v] message [%v]", commit.Date, commit.Message)
+	}
+
+	// COMPARE two revisions
+	diff, err := c.Commits.Diff(ctx, "marcus", "project-x", "feature..master")
+	if err != nil || diff != sampleDiff {
+		t.Errorf("diff [%v] [%v]; want [%v]", diff, err, sampleDiff)
+	}
+	stats, err := c.Commits.Diffstat(ctx, "marcus", "project-x", "feature..master", nil).All()
+	if err != nil || len(stats) != 2 {
+		t.Errorf("diffstat [%v] [%v]; want 2 files", stats, err)
+	}
+}
+
+var sampleCommit = `
+{
+    "type": "commit",
+    "hash": "620ade18607ac42d872b568bb92acaa9a28620e9",
+    "date": "2012-05-30T03:58:56+00:00",
+    "message": "Added some more things to somefile.py\n",
+    "author": {
+        "raw": "Marcus Bertrand <marcus@somedomain.com>",
+        "user": {"uuid": "{a1b2}", "nickname": "marcus", "display_name": "Marcus Bertrand"}
+    },
+    "parents": [
+        {"hash": "702c70160afc", "type": "commit"}
+    ],
+    "repository": {"full_name": "marcus/project-x", "name": "Project X"}
+}
+`
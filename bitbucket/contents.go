@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -20,11 +21,11 @@ type SourceResource struct {
 }
 
 // Gets information about an individual file in a repository
-func (r *SourceResource) Find(owner, slug, revision, path string) (*Source, error) {
+func (r *SourceResource) Find(ctx context.Context, owner, slug, revision, path string) (*Source, error) {
 	src := Source{}
 	url_path := fmt.Sprintf("/repositories/%s/%s/src/%s/%s", owner, slug, revision, path)
 
-	if err := r.client.do("GET", url_path, nil, nil, &src); err != nil {
+	if err := r.client.do(ctx, "GET", url_path, nil, nil, &src); err != nil {
 		return nil, err
 	}
 
@@ -32,10 +33,10 @@ func (r *SourceResource) Find(owner, slug, revision, path string) (*Source, erro
 }
 
 // Gets a list of the src in a repository.
-func (r *SourceResource) List(owner, slug, revision, path string) ([]*Source, error) {
+func (r *SourceResource) List(ctx context.Context, owner, slug, revision, path string) ([]*Source, error) {
 	src := []*Source{}
 	url_path := fmt.Sprintf("/repositories/%s/%s/src/%s/%s", owner, slug, revision, path)
-	if err := r.client.do("GET", url_path, nil, nil, &src); err != nil {
+	if err := r.client.do(ctx, "GET", url_path, nil, nil, &src); err != nil {
 		return nil, err
 	}
 
@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -13,7 +14,7 @@ func Test_Contents(t *testing.T) {
 	// GET the latest revision for the repo
 
 	// GET the README file for the repo & revision
-	src, err := client.Sources.Find("atlassian", "jetbrains-bitbucket-connector", testRev, testFile)
+	src, err := client.Sources.Find(context.Background(), "atlassian", "jetbrains-bitbucket-connector", testRev, testFile)
 	if err != nil {
 		t.Error(err)
 		return
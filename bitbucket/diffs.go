@@ -0,0 +1,50 @@
+package bitbucket
+
+import (
+	"io"
+	"io/ioutil"
+)
+
+// The status of a file in a DiffStat.
+const (
+	DiffStatusAdded    = "added"
+	DiffStatusRemoved  = "removed"
+	DiffStatusModified = "modified"
+	DiffStatusRenamed  = "renamed"
+)
+
+// DiffStat summarises the changes made to a single file.
+type DiffStat struct {
+	// One of the DiffStatus constants.
+	Status string `json:"status"`
+
+	LinesAdded   int `json:"lines_added"`
+	LinesRemoved int `json:"lines_removed"`
+
+	// The file before and after the change. Old is nil for added
+	// files, and New is nil for removed files.
+	Old *DiffStatFile `json:"old"`
+	New *DiffStatFile `json:"new"`
+}
+
+type DiffStatFile struct {
+	Path        string `json:"path"`
+	EscapedPath string `json:"escaped_path"`
+
+	// Either commit_file or commit_directory.
+	Type string `json:"type"`
+}
+
+// readString reads the stream to the end, and closes it.
+func readString(rc io.ReadCloser, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	b, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 )
@@ -26,11 +27,11 @@ type Email struct {
 
 // Gets the email addresses associated with the account. This call requires
 // authentication.
-func (r *EmailResource) List(account string) ([]*Email, error) {
+func (r *EmailResource) List(ctx context.Context, account string) ([]*Email, error) {
 	emails := []*Email{}
 	path := fmt.Sprintf("/users/%s/emails", account)
 
-	if err := r.client.do("GET", path, nil, nil, &emails); err != nil {
+	if err := r.client.do(ctx, "GET", path, nil, nil, &emails); err != nil {
 		return nil, err
 	}
 
@@ -39,11 +40,11 @@ func (r *EmailResource) List(account string) ([]*Email, error) {
 
 // Gets an individual email address associated with an account.
 // This call requires authentication.
-func (r *EmailResource) Find(account, address string) (*Email, error) {
+func (r *EmailResource) Find(ctx context.Context, account, address string) (*Email, error) {
 	email := Email{}
 	path := fmt.Sprintf("/users/%s/emails/%s", account, address)
 
-	if err := r.client.do("GET", path, nil, nil, &email); err != nil {
+	if err := r.client.do(ctx, "GET", path, nil, nil, &email); err != nil {
 		return nil, err
 	}
 
@@ -51,8 +52,8 @@ func (r *EmailResource) Find(account, address string) (*Email, error) {
 }
 
 // Gets an individual's primary email address.
-func (r *EmailResource) FindPrimary(account string) (*Email, error) {
-	emails, err := r.List(account)
+func (r *EmailResource) FindPrimary(ctx context.Context, account string) (*Email, error) {
+	emails, err := r.List(ctx, account)
 	if err != nil {
 		return nil, err
 	}
@@ -68,14 +69,14 @@ func (r *EmailResource) FindPrimary(account string) (*Email, error) {
 
 // Adds additional email addresses to an account. This call requires
 // authentication.
-func (r *EmailResource) Create(account, address string) (*Email, error) {
+func (r *EmailResource) Create(ctx context.Context, account, address string) (*Email, error) {
 
 	values := url.Values{}
 	values.Add("email", address)
 
 	e := Email{}
 	path := fmt.Sprintf("/users/%s/emails/%s", account, address)
-	if err := r.client.do("POST", path, nil, values, &e); err != nil {
+	if err := r.client.do(ctx, "POST", path, nil, values, &e); err != nil {
 		return nil, err
 	}
 
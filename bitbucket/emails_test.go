@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"testing"
 )
 
@@ -9,8 +10,8 @@ func Test_Emails(t *testing.T) {
 	const dummyEmail = "dummy@localhost.com"
 
 	// CREATE an email entry
-	if _, err := client.Emails.Find(testUser, dummyEmail); err != nil {
-		_, cerr := client.Emails.Create(testUser, dummyEmail)
+	if _, err := client.Emails.Find(context.Background(), testUser, dummyEmail); err != nil {
+		_, cerr := client.Emails.Create(context.Background(), testUser, dummyEmail)
 		if cerr != nil {
 			t.Error(cerr)
 			return
@@ -18,13 +19,13 @@ func Test_Emails(t *testing.T) {
 	}
 
 	// FIND the email
-	_, err := client.Emails.Find(testUser, dummyEmail)
+	_, err := client.Emails.Find(context.Background(), testUser, dummyEmail)
 	if err != nil {
 		t.Error(err)
 	}
 
 	// LIST the email addresses
-	emails, err := client.Emails.List(testUser)
+	emails, err := client.Emails.List(context.Background(), testUser)
 	if err != nil {
 		t.Error(err)
 	}
@@ -0,0 +1,117 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when Bitbucket responds with a non-2xx status
+// code. It carries the raw response along with the error message that
+// Bitbucket reported, if any.
+//
+// An APIError matches the ErrNotFound, ErrForbidden, ErrNotAuthorized and
+// ErrBadRequest sentinels when used with errors.Is, so existing checks
+// such as errors.Is(err, ErrNotFound) continue to work.
+type APIError struct {
+	// The HTTP status code of the response.
+	StatusCode int
+
+	// The method and URL of the request that failed.
+	Method string
+	URL    string
+
+	// The raw response body and headers.
+	Body   []byte
+	Header http.Header
+
+	// The error message and detail reported by Bitbucket.
+	Message string
+	Detail  string
+
+	// Validation errors keyed by the name of the offending field.
+	Fields map[string][]string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("bitbucket: %s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if len(e.Message) != 0 {
+		msg += ": " + e.Message
+	}
+	if len(e.Detail) != 0 {
+		msg += " (" + e.Detail + ")"
+	}
+	return msg
+}
+
+// Is reports whether the APIError corresponds to one of the
+// package's sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotAuthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	}
+	return false
+}
+
+// newAPIError creates an APIError from a failed http.Response and
+// the bytes read from its body.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		Header:     resp.Header,
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = resp.Request.URL.String()
+	}
+
+	// Bitbucket reports errors as {"error": {"message": ...}}, though
+	// some 1.0 endpoints respond with plain text instead.
+	data := struct {
+		Error *struct {
+			Message string                     `json:"message"`
+			Detail  string                     `json:"detail"`
+			Fields  map[string]json.RawMessage `json:"fields"`
+		} `json:"error"`
+	}{}
+
+	if err := json.Unmarshal(body, &data); err == nil && data.Error != nil {
+		e.Message = data.Error.Message
+		e.Detail = data.Error.Detail
+		for name, raw := range data.Error.Fields {
+			if e.Fields == nil {
+				e.Fields = map[string][]string{}
+			}
+			e.Fields[name] = unmarshalFieldErrors(raw)
+		}
+	} else if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		e.Message = strings.TrimSpace(string(body))
+	}
+
+	return e
+}
+
+// unmarshalFieldErrors parses a field's validation errors, which
+// Bitbucket sends either as a single string or a list of strings.
+func unmarshalFieldErrors(raw json.RawMessage) []string {
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return []string{str}
+	}
+
+	return []string{string(raw)}
+}
@@ -0,0 +1,81 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/1.0/users/missing":
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("User not found\n"))
+		case "/1.0/users/invalid":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"type": "error", "error": {"message": "Bad request", "detail": "name is invalid", "fields": {"name": ["too long"], "scm": "unknown"}}}`))
+		default:
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(&Anonymous{}, WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+	ctx := context.Background()
+
+	// 404 with a plain text body
+	_, err := c.Users.Find(ctx, "missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected error [%v]; got [%v]", ErrNotFound, err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError; got [%T]", err)
+	}
+	if apiErr.Method != "GET" {
+		t.Errorf("method [%v]; want [%v]", apiErr.Method, "GET")
+	}
+	if apiErr.URL != srv.URL+"/1.0/users/missing" {
+		t.Errorf("url [%v]; want [%v]", apiErr.URL, srv.URL+"/1.0/users/missing")
+	}
+	if apiErr.Message != "User not found" {
+		t.Errorf("message [%v]; want [%v]", apiErr.Message, "User not found")
+	}
+
+	// 400 with a JSON body
+	_, err = c.Users.Find(ctx, "invalid")
+	if !errors.Is(err, ErrBadRequest) || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected error [%v]; got [%v]", ErrBadRequest, err)
+	}
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError; got [%T]", err)
+	}
+	if apiErr.Message != "Bad request" || apiErr.Detail != "name is invalid" {
+		t.Errorf("message [%v] detail [%v]; want [%v] [%v]", apiErr.Message, apiErr.Detail, "Bad request", "name is invalid")
+	}
+	if f := apiErr.Fields["name"]; len(f) != 1 || f[0] != "too long" {
+		t.Errorf("name field errors [%v]; want [%v]", f, []string{"too long"})
+	}
+	if f := apiErr.Fields["scm"]; len(f) != 1 || f[0] != "unknown" {
+		t.Errorf("scm field errors [%v]; want [%v]", f, []string{"unknown"})
+	}
+
+	// 429 is no longer treated as a success
+	_, err = c.Users.Find(ctx, "limited")
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError; got [%v]", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("status [%v]; want [%v]", apiErr.StatusCode, http.StatusTooManyRequests)
+	}
+	if apiErr.Header.Get("Retry-After") != "30" {
+		t.Errorf("Retry-After header [%v]; want [%v]", apiErr.Header.Get("Retry-After"), "30")
+	}
+}
@@ -0,0 +1,257 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ErrUnknownEvent is returned by ParseWebhook for a delivery whose
+// X-Event-Key is not one of the Event constants.
+var ErrUnknownEvent = errors.New("unknown webhook event")
+
+// Event is a webhook delivery decoded by ParseWebhook. It is one of
+// the *Event types in this package, ie *PushEvent.
+type Event interface {
+	// Info gets the fields common to every event.
+	Info() *EventInfo
+}
+
+// EventInfo holds the fields common to every event.
+type EventInfo struct {
+	// The X-Event-Key of the delivery, one of the Event constants.
+	Key string `json:"-"`
+
+	// The X-Request-UUID of the delivery, which is the same each time a
+	// delivery is retried, and the X-Hook-UUID of the webhook.
+	RequestUUID string `json:"-"`
+	HookUUID    string `json:"-"`
+
+	// The user that triggered the event, and the repository it
+	// occurred in.
+	Actor      *User `json:"actor"`
+	Repository *Repo `json:"repository"`
+}
+
+func (e *EventInfo) Info() *EventInfo {
+	return e
+}
+
+// PushEvent is delivered for repo:push, when one or more refs are
+// pushed to a repository.
+type PushEvent struct {
+	EventInfo
+
+	Push struct {
+		Changes []*PushChange `json:"changes"`
+	} `json:"push"`
+}
+
+// PushChange is the change to a single branch or tag in a push.
+type PushChange struct {
+	// The ref after the push, nil if it was deleted, and before the
+	// push, nil if it was created.
+	New *Ref `json:"new"`
+	Old *Ref `json:"old"`
+
+	Created bool `json:"created"`
+	Closed  bool `json:"closed"`
+	Forced  bool `json:"forced"`
+
+	// The commits pushed, newest first. Bitbucket includes at most five
+	// commits, and sets Truncated if there were more.
+	Commits   []*RepoCommit `json:"commits"`
+	Truncated bool          `json:"truncated"`
+
+	Links *Links `json:"links,omitempty"`
+}
+
+// ForkEvent is delivered for repo:fork, when a repository is forked.
+// The Repository is the original, and Fork the new repository.
+type ForkEvent struct {
+	EventInfo
+
+	Fork *Repo `json:"fork"`
+}
+
+// FieldChange is the old and new value of a field changed by a
+// repo:updated or issue:updated event.
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// RepoUpdatedEvent is delivered for repo:updated, when the details of
+// a repository change. Changes is keyed by the name of the field, ie
+// "description".
+type RepoUpdatedEvent struct {
+	EventInfo
+
+	Changes map[string]*FieldChange `json:"changes"`
+}
+
+// CommitCommentEvent is delivered for repo:commit_comment_created, when
+// a commit is commented on.
+type CommitCommentEvent struct {
+	EventInfo
+
+	Comment *Comment   `json:"comment"`
+	Commit  *CommitRef `json:"commit"`
+}
+
+// CommitStatusEvent is delivered for repo:commit_status_created and
+// repo:commit_status_updated, when a build status is reported against a
+// commit.
+type CommitStatusEvent struct {
+	EventInfo
+
+	CommitStatus *CommitStatus `json:"commit_status"`
+}
+
+// Approval records a user approving, or requesting changes to, a pull
+// request.
+type Approval struct {
+	Date time.Time `json:"date"`
+	User *User     `json:"user"`
+}
+
+// PullRequestEvent is delivered for the pullrequest:* events, other
+// than those for comments.
+type PullRequestEvent struct {
+	EventInfo
+
+	PullRequest *PullRequest `json:"pullrequest"`
+
+	// Set for pullrequest:approved and pullrequest:unapproved.
+	Approval *Approval `json:"approval,omitempty"`
+
+	// Set for pullrequest:changes_request_created and
+	// pullrequest:changes_request_removed.
+	ChangesRequest *Approval `json:"changes_request,omitempty"`
+}
+
+// PullRequestCommentEvent is delivered for the pullrequest:comment_*
+// events.
+type PullRequestCommentEvent struct {
+	EventInfo
+
+	PullRequest *PullRequest `json:"pullrequest"`
+	Comment     *Comment     `json:"comment"`
+}
+
+// Issue is an issue in a repository's issue tracker.
+type Issue struct {
+	Id       int      `json:"id"`
+	Title    string   `json:"title"`
+	Content  *Content `json:"content"`
+	State    string   `json:"state"`
+	Kind     string   `json:"kind"`
+	Priority string   `json:"priority"`
+	Reporter *User    `json:"reporter"`
+	Assignee *User    `json:"assignee"`
+
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+	Links     *Links    `json:"links,omitempty"`
+}
+
+// IssueEvent is delivered for issue:created and issue:updated. Changes
+// and Comment are only set for issue:updated; Changes is keyed by the
+// name of the field, ie "status", and Comment is the comment left with
+// the changes, if any.
+type IssueEvent struct {
+	EventInfo
+
+	Issue   *Issue                  `json:"issue"`
+	Changes map[string]*FieldChange `json:"changes,omitempty"`
+	Comment *Comment                `json:"comment,omitempty"`
+}
+
+// IssueCommentEvent is delivered for issue:comment_created.
+type IssueCommentEvent struct {
+	EventInfo
+
+	Issue   *Issue   `json:"issue"`
+	Comment *Comment `json:"comment"`
+}
+
+// events creates an empty event of the type delivered for each
+// event key.
+var events = map[string]func() Event{
+	EventRepoPush:                         func() Event { return &PushEvent{} },
+	EventRepoFork:                         func() Event { return &ForkEvent{} },
+	EventRepoUpdated:                      func() Event { return &RepoUpdatedEvent{} },
+	EventRepoCommitCommentCreated:         func() Event { return &CommitCommentEvent{} },
+	EventRepoCommitStatusCreated:          func() Event { return &CommitStatusEvent{} },
+	EventRepoCommitStatusUpdated:          func() Event { return &CommitStatusEvent{} },
+	EventPullRequestCreated:               func() Event { return &PullRequestEvent{} },
+	EventPullRequestUpdated:               func() Event { return &PullRequestEvent{} },
+	EventPullRequestApproved:              func() Event { return &PullRequestEvent{} },
+	EventPullRequestUnapproved:            func() Event { return &PullRequestEvent{} },
+	EventPullRequestChangesRequestCreated: func() Event { return &PullRequestEvent{} },
+	EventPullRequestChangesRequestRemoved: func() Event { return &PullRequestEvent{} },
+	EventPullRequestFulfilled:             func() Event { return &PullRequestEvent{} },
+	EventPullRequestRejected:              func() Event { return &PullRequestEvent{} },
+	EventPullRequestCommentCreated:        func() Event { return &PullRequestCommentEvent{} },
+	EventPullRequestCommentUpdated:        func() Event { return &PullRequestCommentEvent{} },
+	EventPullRequestCommentDeleted:        func() Event { return &PullRequestCommentEvent{} },
+	EventPullRequestCommentResolved:       func() Event { return &PullRequestCommentEvent{} },
+	EventPullRequestCommentReopened:       func() Event { return &PullRequestCommentEvent{} },
+	EventIssueCreated:                     func() Event { return &IssueEvent{} },
+	EventIssueUpdated:                     func() Event { return &IssueEvent{} },
+	EventIssueCommentCreated:              func() Event { return &IssueCommentEvent{} },
+}
+
+// ParseWebhook decodes a webhook delivery into the event type for its
+// X-Event-Key header, ie a *PushEvent for repo:push:
+//
+//	event, err := bitbucket.ParseWebhook(r)
+//	if err != nil {
+//		...
+//	}
+//	switch e := event.(type) {
+//	case *bitbucket.PushEvent:
+//		...
+//	case *bitbucket.PullRequestEvent:
+//		...
+//	}
+//
+// An error matching ErrUnknownEvent is returned if the event key is
+// missing or not one of the Event constants. The request body is read,
+// but not closed.
+func ParseWebhook(r *http.Request) (Event, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := ParseEvent(r.Header.Get("X-Event-Key"), body)
+	if err != nil {
+		return nil, err
+	}
+
+	info := event.Info()
+	info.RequestUUID = r.Header.Get("X-Request-UUID")
+	info.HookUUID = r.Header.Get("X-Hook-UUID")
+	return event, nil
+}
+
+// ParseEvent decodes the payload of a webhook delivery into the event
+// type for the event key. See ParseWebhook.
+func ParseEvent(key string, payload []byte) (Event, error) {
+	newEvent, ok := events[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEvent, key)
+	}
+
+	event := newEvent()
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, fmt.Errorf("decoding %s event: %w", key, err)
+	}
+
+	event.Info().Key = key
+	return event, nil
+}
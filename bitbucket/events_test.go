@@ -0,0 +1,125 @@
+package bitbucket
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_ParseWebhookPush(t *testing.T) {
+	r := httptest.NewRequest("POST", "/hook", strings.NewReader(samplePushEvent))
+	r.Header.Set("X-Event-Key", EventRepoPush)
+	r.Header.Set("X-Request-UUID", "{r1}")
+
+	event, err := ParseWebhook(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	push, ok := event.(*PushEvent)
+	if !ok {
+		t.Fatalf("event [%T]; want [%T]", event, push)
+	}
+	if push.Key != EventRepoPush || push.RequestUUID != "{r1}" || push.Actor.Nickname != "marcus" {
+		t.Errorf("event key [%v] request [%v] actor [%v]", push.Key, push.RequestUUID, push.Actor)
+	}
+	if push.Repository.Owner != "marcus" || push.Repository.Slug != "project-x" {
+		t.Errorf("repository owner [%v] slug [%v]", push.Repository.Owner, push.Repository.Slug)
+	}
+
+	if len(push.Push.Changes) != 2 {
+		t.Fatalf("changes %v", push.Push.Changes)
+	}
+	change := push.Push.Changes[0]
+	if change.New.Name != "master" || change.New.Hash() != "620ade18607a" || change.Old.Hash() != "702c70160afc" {
+		t.Errorf("change new [%v] old [%v]", change.New, change.Old)
+	}
+	if !change.Truncated || len(change.Commits) != 1 || change.Commits[0].Message != "Added some more things\n" {
+		t.Errorf("change truncated [%v] commits %v", change.Truncated, change.Commits)
+	}
+	if deleted := push.Push.Changes[1]; !deleted.Closed || deleted.New != nil || deleted.Old.Type != RefTypeTag {
+		t.Errorf("expected a deleted tag; got %v", deleted)
+	}
+}
+
+func Test_ParseWebhookPullRequest(t *testing.T) {
+	r := httptest.NewRequest("POST", "/hook", strings.NewReader(samplePullRequestEvent))
+	r.Header.Set("X-Event-Key", EventPullRequestApproved)
+
+	event, err := ParseWebhook(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, ok := event.(*PullRequestEvent)
+	if !ok {
+		t.Fatalf("event [%T]; want [%T]", event, pr)
+	}
+	if pr.PullRequest.Id != 7 || pr.PullRequest.Source.Branch.Name != "feature" || pr.Approval.User.Nickname != "jane" {
+		t.Errorf("pull request [%v] approval [%v]", pr.PullRequest, pr.Approval)
+	}
+}
+
+func Test_ParseWebhookUnknown(t *testing.T) {
+	for _, key := range []string{"", "repo:imported"} {
+		r := httptest.NewRequest("POST", "/hook", strings.NewReader(`{}`))
+		r.Header.Set("X-Event-Key", key)
+		if _, err := ParseWebhook(r); !errors.Is(err, ErrUnknownEvent) {
+			t.Errorf("event [%v] error [%v]; want [%v]", key, err, ErrUnknownEvent)
+		}
+	}
+}
+
+var samplePushEvent = `
+{
+    "actor": {"type": "user", "nickname": "marcus", "uuid": "{a1b2}"},
+    "repository": {
+        "type": "repository",
+        "full_name": "marcus/project-x",
+        "name": "Project X",
+        "uuid": "{e5f6}",
+        "is_private": true
+    },
+    "push": {
+        "changes": [
+            {
+                "new": {"type": "branch", "name": "master", "target": {"type": "commit", "hash": "620ade18607a"}},
+                "old": {"type": "branch", "name": "master", "target": {"type": "commit", "hash": "702c70160afc"}},
+                "created": false,
+                "closed": false,
+                "forced": false,
+                "truncated": true,
+                "commits": [
+                    {"type": "commit", "hash": "620ade18607a", "message": "Added some more things\n"}
+                ]
+            },
+            {
+                "new": null,
+                "old": {"type": "tag", "name": "v1.0", "target": {"type": "commit", "hash": "702c70160afc"}},
+                "created": false,
+                "closed": true,
+                "forced": false,
+                "truncated": false,
+                "commits": []
+            }
+        ]
+    }
+}
+`
+
+var samplePullRequestEvent = `
+{
+    "actor": {"type": "user", "nickname": "jane", "uuid": "{c3d4}"},
+    "repository": {"type": "repository", "full_name": "marcus/project-x", "name": "Project X"},
+    "pullrequest": {
+        "id": 7,
+        "title": "Add a feature",
+        "state": "OPEN",
+        "source": {"branch": {"name": "feature"}, "commit": {"hash": "620ade18607a"}},
+        "destination": {"branch": {"name": "master"}, "commit": {"hash": "702c70160afc"}}
+    },
+    "approval": {
+        "date": "2024-03-01T12:00:00.000000+00:00",
+        "user": {"type": "user", "nickname": "jane", "uuid": "{c3d4}"}
+    }
+}
+`
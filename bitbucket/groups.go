@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 )
@@ -27,49 +28,49 @@ type GroupDetails struct {
 }
 
 // Create - Creates a group
-func (gr *GroupResource) Create(owner string, name string) (g *GroupDetails, err error) {
-	ownerOrCurrentUser(gr, &owner)
+func (gr *GroupResource) Create(ctx context.Context, owner string, name string) (g *GroupDetails, err error) {
+	ownerOrCurrentUser(ctx, gr, &owner)
 
 	path := fmt.Sprintf("/groups/%s/", owner)
 	values := url.Values{}
 	values.Set("name", name)
-	err = gr.client.do("POST", path, nil, values, &g)
+	err = gr.client.do(ctx, "POST", path, nil, values, &g)
 
 	return
 }
 
 // Update - Update group
-func (gr *GroupResource) Update(group *GroupDetails) (g *GroupDetails, err error) {
+func (gr *GroupResource) Update(ctx context.Context, group *GroupDetails) (g *GroupDetails, err error) {
 	owner := group.Owner.Username
-	ownerOrCurrentUser(gr, &owner)
+	ownerOrCurrentUser(ctx, gr, &owner)
 
 	path := fmt.Sprintf("/groups/%s/%s", owner, group.Slug)
 	body := url.Values{}
 	body.Set("permission", group.Permission)
-	err = gr.client.do("PUT", path, nil, body, &g)
+	err = gr.client.do(ctx, "PUT", path, nil, body, &g)
 	return
 }
 
 // Delete - Deletes a group from an account
-func (gr *GroupResource) Delete(owner string, slug string) error {
-	ownerOrCurrentUser(gr, &owner)
+func (gr *GroupResource) Delete(ctx context.Context, owner string, slug string) error {
+	ownerOrCurrentUser(ctx, gr, &owner)
 
 	path := fmt.Sprintf("/groups/%s/%s", owner, slug)
-	return gr.client.do("DELETE", path, nil, nil, nil)
+	return gr.client.do(ctx, "DELETE", path, nil, nil, nil)
 }
 
 // List - Lists groups by owner
-func (gr *GroupResource) List(owner string) (g []*GroupDetails, err error) {
-	ownerOrCurrentUser(gr, &owner)
+func (gr *GroupResource) List(ctx context.Context, owner string) (g []*GroupDetails, err error) {
+	ownerOrCurrentUser(ctx, gr, &owner)
 
 	path := fmt.Sprintf("/groups/%s/", owner)
-	err = gr.client.do("GET", path, nil, nil, &g)
+	err = gr.client.do(ctx, "GET", path, nil, nil, &g)
 	return g, nil
 }
 
 // Get - Retrieve a group by owner and slug
-func (gr *GroupResource) Get(owner string, slug string) (*Group, error) {
-	ownerOrCurrentUser(gr, &owner)
+func (gr *GroupResource) Get(ctx context.Context, owner string, slug string) (*Group, error) {
+	ownerOrCurrentUser(ctx, gr, &owner)
 
 	filter := fmt.Sprintf("%s/%s", owner, slug)
 	params := url.Values{
@@ -77,7 +78,7 @@ func (gr *GroupResource) Get(owner string, slug string) (*Group, error) {
 	}
 
 	var groups []Group
-	err := gr.client.do("GET", "/groups", params, nil, &groups)
+	err := gr.client.do(ctx, "GET", "/groups", params, nil, &groups)
 	if err != nil {
 		return nil, err
 	}
@@ -92,36 +93,36 @@ func (gr *GroupResource) Get(owner string, slug string) (*Group, error) {
 }
 
 // AddMember - Add a member to an existing group
-func (gr *GroupResource) AddMember(owner string, group string, member string) (user *User, err error) {
-	ownerOrCurrentUser(gr, &owner)
+func (gr *GroupResource) AddMember(ctx context.Context, owner string, group string, member string) (user *User, err error) {
+	ownerOrCurrentUser(ctx, gr, &owner)
 
 	path := fmt.Sprintf("/groups/%s/%s/members/%s", owner, group, member)
-	err = gr.client.do("PUT", path, nil, nil, &user)
+	err = gr.client.do(ctx, "PUT", path, nil, nil, &user)
 	return
 }
 
 // GetMembers - Retrieve the members of a group
-func (gr *GroupResource) GetMembers(owner string, group string) (members *[]User, err error) {
-	ownerOrCurrentUser(gr, &owner)
+func (gr *GroupResource) GetMembers(ctx context.Context, owner string, group string) (members *[]User, err error) {
+	ownerOrCurrentUser(ctx, gr, &owner)
 
 	path := fmt.Sprintf("/groups/%s/%s/members/", owner, group)
-	err = gr.client.do("GET", path, nil, nil, &members)
+	err = gr.client.do(ctx, "GET", path, nil, nil, &members)
 	return
 }
 
 // RemoveMember - Remove a member from an existing group
-func (gr *GroupResource) RemoveMember(owner string, group string, member string) (err error) {
-	ownerOrCurrentUser(gr, &owner)
+func (gr *GroupResource) RemoveMember(ctx context.Context, owner string, group string, member string) (err error) {
+	ownerOrCurrentUser(ctx, gr, &owner)
 
 	path := fmt.Sprintf("/groups/%s/%s/members/%s", owner, group, member)
-	err = gr.client.do("DELETE", path, nil, nil, nil)
+	err = gr.client.do(ctx, "DELETE", path, nil, nil, nil)
 	return
 }
 
 // ownerOrCurrentUser sets the owner string to the current user when empty
-func ownerOrCurrentUser(gr *GroupResource, owner *string) error {
+func ownerOrCurrentUser(ctx context.Context, gr *GroupResource, owner *string) error {
 	if owner == nil || (*owner) == "" {
-		current, err := gr.client.Users.Current()
+		current, err := gr.client.Users.Current(ctx)
 		if err == nil {
 			return err
 		}
@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"testing"
@@ -26,8 +27,8 @@ func TestGroupsCreate(t *testing.T) {
 		t.Skip("skipping in short mode")
 	}
 	testGroup := "TestGroupsCreate"
-	group, err := client.Groups.Create(testUser, testGroup)
-	defer client.Groups.Delete(testUser, group.Slug)
+	group, err := client.Groups.Create(context.Background(), testUser, testGroup)
+	defer client.Groups.Delete(context.Background(), testUser, group.Slug)
 
 	// create
 	assert.NoError(t, err)
@@ -45,15 +46,15 @@ func TestGroupsUpdate(t *testing.T) {
 		t.Skip("skipping in short mode")
 	}
 	testGroup := "TestGroupsUpdate"
-	group, err := client.Groups.Create(testUser, testGroup)
+	group, err := client.Groups.Create(context.Background(), testUser, testGroup)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, group)
 
 	group.Permission = "read"
-	client.Groups.Update(group)
+	client.Groups.Update(context.Background(), group)
 
-	group, err = client.Groups.Find(testUser, group.Slug)
+	group, err = client.Groups.Find(context.Background(), testUser, group.Slug)
 	assert.NoError(t, err)
 	assert.NotNil(t, group)
 	assert.Equal(t, "Read", group.Permission)
@@ -64,13 +65,13 @@ func TestGroupsDelete(t *testing.T) {
 		t.Skip("skipping in short mode")
 	}
 	testGroup := "TestGroupsDelete"
-	group, err := client.Groups.Create(testUser, testGroup)
-	defer client.Groups.Delete(testUser, group.Slug)
+	group, err := client.Groups.Create(context.Background(), testUser, testGroup)
+	defer client.Groups.Delete(context.Background(), testUser, group.Slug)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, group)
 
-	err = client.Groups.Delete(testUser, group.Slug)
+	err = client.Groups.Delete(context.Background(), testUser, group.Slug)
 	assert.NoError(t, err)
 }
 
@@ -79,10 +80,10 @@ func TestGroupsList(t *testing.T) {
 		t.Skip("skipping in short mode")
 	}
 	testGroup := "TestGroupsList"
-	group, err := client.Groups.Create(testUser, testGroup)
-	defer client.Groups.Delete(testUser, group.Slug)
+	group, err := client.Groups.Create(context.Background(), testUser, testGroup)
+	defer client.Groups.Delete(context.Background(), testUser, group.Slug)
 
-	groups, err := client.Groups.List(testUser)
+	groups, err := client.Groups.List(context.Background(), testUser)
 	if err != nil {
 		t.Error(err)
 		return
@@ -95,13 +96,13 @@ func TestGroupsAddMembers(t *testing.T) {
 		t.Skip("skipping in short mode")
 	}
 	testGroup := "TestGroupsAddMember"
-	group, err := client.Groups.Create(testUser, testGroup)
-	defer client.Groups.Delete(testUser, group.Slug)
+	group, err := client.Groups.Create(context.Background(), testUser, testGroup)
+	defer client.Groups.Delete(context.Background(), testUser, group.Slug)
 
-	_, err = client.Groups.AddMember(testUser, group.Slug, testUser)
+	_, err = client.Groups.AddMember(context.Background(), testUser, group.Slug, testUser)
 	assert.NoError(t, err)
 
-	members, err := client.Groups.GetMembers(testUser, group.Slug)
+	members, err := client.Groups.GetMembers(context.Background(), testUser, group.Slug)
 	assert.NoError(t, err)
 	assert.NotNil(t, members)
 	assert.True(t, len(*members) == 1)
@@ -113,22 +114,22 @@ func TestGroupsRemoveMember(t *testing.T) {
 		t.Skip("skipping in short mode")
 	}
 	testGroup := "TestGroupsRemoveMember"
-	created, err := client.Groups.Create(testUser, testGroup)
-	defer client.Groups.Delete(testUser, created.Slug)
+	created, err := client.Groups.Create(context.Background(), testUser, testGroup)
+	defer client.Groups.Delete(context.Background(), testUser, created.Slug)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, created)
 
-	_, err = client.Groups.AddMember(testUser, created.Slug, testUser)
+	_, err = client.Groups.AddMember(context.Background(), testUser, created.Slug, testUser)
 	assert.NoError(t, err)
 
-	members, err := client.Groups.GetMembers(testUser, created.Slug)
+	members, err := client.Groups.GetMembers(context.Background(), testUser, created.Slug)
 	assert.Equal(t, 1, len(*members))
 
-	err = client.Groups.RemoveMember(testUser, created.Slug, testUser)
+	err = client.Groups.RemoveMember(context.Background(), testUser, created.Slug, testUser)
 	assert.NoError(t, err)
 
-	members, err = client.Groups.GetMembers(testUser, created.Slug)
+	members, err = client.Groups.GetMembers(context.Background(), testUser, created.Slug)
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(*members))
 }
@@ -138,10 +139,10 @@ func TestGroupsGet(t *testing.T) {
 		t.Skip("skipping in short mode")
 	}
 	testGroup := "TestGroupsFind"
-	created, err := client.Groups.Create(testUser, testGroup)
-	defer client.Groups.Delete(testUser, created.Slug)
+	created, err := client.Groups.Create(context.Background(), testUser, testGroup)
+	defer client.Groups.Delete(context.Background(), testUser, created.Slug)
 
-	group, err := client.Groups.Get(testUser, created.Slug)
+	group, err := client.Groups.Get(context.Background(), testUser, created.Slug)
 	assert.NotNil(t, group)
 	assert.NoError(t, err)
 	assert.Equal(t, testGroup, group.Name)
@@ -2,9 +2,9 @@ package bitbucket
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -32,25 +32,181 @@ var (
 	ErrBadRequest = errors.New("Bad Request")
 )
 
-// DefaultClient uses DefaultTransport, and is used internall to execute
-// all http.Requests. This may be overriden for unit testing purposes.
+// DefaultClient uses DefaultTransport, and is used internally to execute
+// http.Requests for any Client created without WithHTTPClient.
 //
-// IMPORTANT: this is not thread safe and should not be touched with
-// the exception overriding for mock unit testing.
+// IMPORTANT: this is not thread safe and should not be touched. Use
+// WithHTTPClient to give a Client its own transport instead.
 var DefaultClient = http.DefaultClient
 
-func (c *Client) do(method string, path string, params url.Values, values interface{}, v interface{}) error {
+// do executes a request against the Bitbucket 1.0 API.
+func (c *Client) do(ctx context.Context, method string, path string, params url.Values, values interface{}, v interface{}) error {
+	return c.doURL(ctx, method, c.baseURL+"/1.0"+path, params, values, v)
+}
 
-	// create the URI
-	uri, err := url.Parse("https://api.bitbucket.org/1.0" + path)
+// do2 executes a request against the Bitbucket 2.0 API.
+func (c *Client) do2(ctx context.Context, method string, path string, params url.Values, values interface{}, v interface{}) error {
+	return c.doURL(ctx, method, c.baseURL+"/2.0"+path, params, values, v)
+}
+
+// stream2 executes a GET request against the Bitbucket 2.0 API and
+// returns the response body unread, so that large responses such as
+// diffs need not be buffered. The caller must close the body.
+func (c *Client) stream2(ctx context.Context, path string, params url.Values) (io.ReadCloser, error) {
+	resp, err := c.send(ctx, "GET", c.baseURL+"/2.0"+path, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// doURL executes a request against an absolute URL, such as the next
+// link of a 2.0 paginated response. If values is a url.Values it is sent
+// as a form, otherwise it is sent as JSON. The response body, if any, is
+// unmarshalled into v.
+func (c *Client) doURL(ctx context.Context, method string, rawurl string, params url.Values, values interface{}, v interface{}) error {
+	resp, err := c.send(ctx, method, rawurl, params, values)
+	if err != nil {
+		return err
+	}
+
+	// Read the bytes from the body (make sure we defer close the body)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
 
+	// Unmarshall the JSON response
+	if v != nil && len(body) != 0 {
+		return json.Unmarshal(body, v)
+	}
+
+	return nil
+}
+
+// send executes a request, retrying it according to the Client's
+// RetryPolicy. If the response has a 2xx status it is returned with its
+// body unread, and the caller must close it. Otherwise an *APIError
+// is returned.
+func (c *Client) send(ctx context.Context, method string, rawurl string, params url.Values, values interface{}) (*http.Response, error) {
+
+	// create the URI
+	uri, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+
 	if params != nil && len(params) > 0 {
 		uri.RawQuery = params.Encode()
 	}
 
+	// construct the body of the request. This is done once, up front,
+	// so that the body can be replayed if the request is retried.
+	var body []byte
+	if values != nil {
+		if v, ok := values.(url.Values); ok {
+			body = []byte(v.Encode())
+		} else {
+			var err error
+			body, err = json.Marshal(values)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(body) != 0 {
+		c.logger.DebugContext(ctx, "bitbucket request",
+			"method", method,
+			"url", redactURL(uri),
+			"body", redactBody(values, body),
+		)
+	} else {
+		c.logger.DebugContext(ctx, "bitbucket request",
+			"method", method,
+			"url", redactURL(uri),
+		)
+	}
+
+	for attempt := 1; ; attempt++ {
+
+		// wait for our turn if the client is rate limited
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		// the request is rebuilt and re-signed for every attempt, since
+		// OAuth requires a fresh nonce and timestamp each time
+		req, err := c.newRequest(ctx, method, uri, values, body)
+		if err != nil {
+			return nil, err
+		}
+
+		// make the request using the client's http client
+		resp, err := c.client().Do(req)
+		if c.limiter != nil {
+			c.limiter.observe(resp)
+		}
+		if err == nil {
+
+			// Check for an http error status (ie not 2xx)
+			if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+				return resp, nil
+			}
+
+			body, rerr := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if rerr != nil {
+				return nil, rerr
+			}
+			err = newAPIError(resp, body)
+		}
+
+		// a cancelled or expired context is never retried
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		wait, ok := c.retry.backoff(method, attempt, resp, err)
+		if !ok {
+			return nil, err
+		}
+
+		event := &RetryEvent{
+			Method:  method,
+			URL:     redactURL(uri),
+			Attempt: attempt,
+			Err:     err,
+			Wait:    wait,
+		}
+		if resp != nil {
+			event.StatusCode = resp.StatusCode
+		}
+		c.logger.WarnContext(ctx, "bitbucket request will be retried",
+			"method", method,
+			"url", redactURL(uri),
+			"attempt", attempt,
+			"status", event.StatusCode,
+			"wait", wait,
+			"error", err,
+		)
+		if c.retry.OnRetry != nil {
+			c.retry.OnRetry(event)
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// newRequest creates a signed http.Request for the given URI and
+// encoded body.
+func (c *Client) newRequest(ctx context.Context, method string, uri *url.URL, values interface{}, body []byte) (*http.Request, error) {
+
 	// create the request
 	req := &http.Request{
 		URL:        uri,
@@ -60,70 +216,44 @@ func (c *Client) do(method string, path string, params url.Values, values interf
 		Close:      true,
 		Header:     make(http.Header),
 	}
+	req = req.WithContext(ctx)
 
-	// construct the body of the request
 	if values != nil {
-		var body []byte
-		if v, ok := values.(url.Values); ok {
-			body = []byte(v.Encode())
+		req.Body = nopCloser(string(body))
+		req.ContentLength = int64(len(body))
 
+		if v, ok := values.(url.Values); ok {
 			// (we'll need this in order to sign the request)
 			req.Form = v
 		} else {
-			var err error
-			body, err = json.Marshal(values)
-			if err != nil {
-				return nil
-			}
+			req.Header.Set("Content-Type", "application/json")
 		}
-		req.Body = nopCloser(string(body))
-		fmt.Printf("request body:%s", string(body))
 	}
 
-	if v, ok := values.(url.Values); ok {
-		req.Form = v
+	// add the client's default headers to the request
+	for k, vals := range c.header {
+		for _, val := range vals {
+			req.Header.Add(k, val)
+		}
 	}
-
-	// add authentication to the request
-	c.auth.authenticate(req)
-
-	// make the request using the default http client
-	resp, err := DefaultClient.Do(req)
-	if err != nil {
-		return err
+	if len(c.userAgent) != 0 {
+		req.Header.Set("User-Agent", c.userAgent)
 	}
 
-	// Read the bytes from the body (make sure we defer close the body)
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
+	// add authentication to the request
+	if err := c.auth.authenticate(ctx, req); err != nil {
+		return nil, err
 	}
 
-	// fmt.Printf("%s %s\n", method, uri)
-	// if uri.String() == "https://api.bitbucket.org/1.0/groups/webdevwilson/testgroupsremovemember" {
-	// 	fmt.Printf("body:%s\n", body)
-	// 	fmt.Printf("status: %d\n", resp.StatusCode)
-	// }
-
-	// Check for an http error status (ie not 200 StatusOK)
-	switch resp.StatusCode {
-	case 404:
-		return ErrNotFound
-	case 403:
-		return ErrForbidden
-	case 401:
-		return ErrNotAuthorized
-	case 400:
-		return ErrBadRequest
-	}
+	return req, nil
+}
 
-	// Unmarshall the JSON response
-	if v != nil {
-		return json.Unmarshal(body, v)
+// client returns the http.Client used to execute requests.
+func (c *Client) client() *http.Client {
+	if c.httpClient != nil {
+		return c.httpClient
 	}
-
-	return nil
+	return DefaultClient
 }
 
 func nopCloser(str string) io.ReadCloser {
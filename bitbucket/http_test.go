@@ -0,0 +1,42 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_DoContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	c := New(&Anonymous{}, WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Users.Current(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error [%v]; got [%v]", context.DeadlineExceeded, err)
+	}
+}
+
+func Test_DoContextOAuth(t *testing.T) {
+	c := New(&OAuth{ConsumerKey: "key", ConsumerSecret: "secret"}, WithBaseURL("http://127.0.0.1:0"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.Users.Current(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error [%v]; got [%v]", context.Canceled, err)
+	}
+}
@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 )
@@ -21,11 +22,11 @@ type KeyResource struct {
 
 // Gets a list of the keys associated with an account.
 // This call requires authentication.
-func (r *KeyResource) List(account string) ([]*Key, error) {
+func (r *KeyResource) List(ctx context.Context, account string) ([]*Key, error) {
 	keys := []*Key{}
 	path := fmt.Sprintf("/users/%s/ssh-keys", account)
 
-	if err := r.client.do("GET", path, nil, nil, &keys); err != nil {
+	if err := r.client.do(ctx, "GET", path, nil, nil, &keys); err != nil {
 		return nil, err
 	}
 
@@ -34,10 +35,10 @@ func (r *KeyResource) List(account string) ([]*Key, error) {
 
 // Gets the content of the specified key_id.
 // This call requires authentication.
-func (r *KeyResource) Find(account string, id int) (*Key, error) {
+func (r *KeyResource) Find(ctx context.Context, account string, id int) (*Key, error) {
 	key := Key{}
 	path := fmt.Sprintf("/users/%s/ssh-keys/%v", account, id)
-	if err := r.client.do("GET", path, nil, nil, &key); err != nil {
+	if err := r.client.do(ctx, "GET", path, nil, nil, &key); err != nil {
 		return nil, err
 	}
 
@@ -46,8 +47,8 @@ func (r *KeyResource) Find(account string, id int) (*Key, error) {
 
 // Gets the content of the specified key with the
 // given label.
-func (r *KeyResource) FindName(account, label string) (*Key, error) {
-	keys, err := r.List(account)
+func (r *KeyResource) FindName(ctx context.Context, account, label string) (*Key, error) {
+	keys, err := r.List(ctx, account)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +64,7 @@ func (r *KeyResource) FindName(account, label string) (*Key, error) {
 
 // Creates a key on the specified account. You must supply a valid key
 // that is unique across the Bitbucket service.
-func (r *KeyResource) Create(account, key, label string) (*Key, error) {
+func (r *KeyResource) Create(ctx context.Context, account, key, label string) (*Key, error) {
 
 	values := url.Values{}
 	values.Add("key", key)
@@ -71,7 +72,7 @@ func (r *KeyResource) Create(account, key, label string) (*Key, error) {
 
 	k := Key{}
 	path := fmt.Sprintf("/users/%s/ssh-keys", account)
-	if err := r.client.do("POST", path, nil, values, &k); err != nil {
+	if err := r.client.do(ctx, "POST", path, nil, values, &k); err != nil {
 		return nil, err
 	}
 
@@ -80,7 +81,7 @@ func (r *KeyResource) Create(account, key, label string) (*Key, error) {
 
 // Creates a key on the specified account. You must supply a valid key
 // that is unique across the Bitbucket service.
-func (r *KeyResource) Update(account, key, label string, id int) (*Key, error) {
+func (r *KeyResource) Update(ctx context.Context, account, key, label string, id int) (*Key, error) {
 
 	values := url.Values{}
 	values.Add("key", key)
@@ -88,18 +89,18 @@ func (r *KeyResource) Update(account, key, label string, id int) (*Key, error) {
 
 	k := Key{}
 	path := fmt.Sprintf("/users/%s/ssh-keys/%v", account, id)
-	if err := r.client.do("PUT", path, nil, values, &k); err != nil {
+	if err := r.client.do(ctx, "PUT", path, nil, values, &k); err != nil {
 		return nil, err
 	}
 
 	return &k, nil
 }
 
-func (r *KeyResource) CreateUpdate(account, key, label string) (*Key, error) {
-	if found, err := r.FindName(account, label); err == nil {
+func (r *KeyResource) CreateUpdate(ctx context.Context, account, key, label string) (*Key, error) {
+	if found, err := r.FindName(ctx, account, label); err == nil {
 		// if the public keys are different we should update
 		if found.Key != key {
-			return r.Update(account, key, label, found.Id)
+			return r.Update(ctx, account, key, label, found.Id)
 		}
 
 		// otherwise we should just return the key, since there
@@ -107,12 +108,12 @@ func (r *KeyResource) CreateUpdate(account, key, label string) (*Key, error) {
 		return found, nil
 	}
 
-	return r.Create(account, key, label)
+	return r.Create(ctx, account, key, label)
 }
 
 // Deletes the key specified by the key_id value.
 // This call requires authentication
-func (r *KeyResource) Delete(account string, id int) error {
+func (r *KeyResource) Delete(ctx context.Context, account string, id int) error {
 	path := fmt.Sprintf("/users/%s/ssh-keys/%v", account, id)
-	return r.client.do("DELETE", path, nil, nil, nil)
+	return r.client.do(ctx, "DELETE", path, nil, nil, nil)
 }
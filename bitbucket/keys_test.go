@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"testing"
 )
 
@@ -11,23 +12,23 @@ func Test_Keys(t *testing.T) {
 	title := "test@localhost"
 
 	// create a new public key
-	key, err := client.Keys.Create(testUser, public, title)
+	key, err := client.Keys.Create(context.Background(), testUser, public, title)
 	if err != nil {
 		t.Error(err)
 		return
 	}
 
 	// cleanup after ourselves & delete this dummy key
-	defer client.Keys.Delete(testUser, key.Id)
+	defer client.Keys.Delete(context.Background(), testUser, key.Id)
 
 	// Get the new key we recently created
-	find, err := client.Keys.Find(testUser, key.Id)
+	find, err := client.Keys.Find(context.Background(), testUser, key.Id)
 	if title != find.Label {
 		t.Errorf("key label [%v]; want [%v]", find.Label, title)
 	}
 
 	// Get a list of SSH keys for the user
-	keys, err := client.Keys.List(testUser)
+	keys, err := client.Keys.List(context.Background(), testUser)
 	if err != nil {
 		t.Error(err)
 	}
@@ -0,0 +1,22 @@
+package bitbucket
+
+// Link is a hyperlink to a related resource in a Bitbucket 2.0
+// response.
+type Link struct {
+	Href string `json:"href"`
+	Name string `json:"name,omitempty"`
+}
+
+// Links are the hyperlinks included with a Bitbucket 2.0 resource.
+// Which links are populated depends on the type of resource.
+type Links struct {
+	Self     *Link   `json:"self,omitempty"`
+	HTML     *Link   `json:"html,omitempty"`
+	Avatar   *Link   `json:"avatar,omitempty"`
+	Clone    []*Link `json:"clone,omitempty"`
+	Commits  *Link   `json:"commits,omitempty"`
+	Comments *Link   `json:"comments,omitempty"`
+	Statuses *Link   `json:"statuses,omitempty"`
+	Diff     *Link   `json:"diff,omitempty"`
+	Patch    *Link   `json:"patch,omitempty"`
+}
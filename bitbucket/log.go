@@ -0,0 +1,132 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Logger receives the Client's diagnostics. It is satisfied by
+// *slog.Logger, so a Client can log through log/slog with:
+//
+//	client := bitbucket.New(auth, bitbucket.WithLogger(slog.Default()))
+//
+// Credentials, OAuth signatures and key material are redacted before
+// they are logged.
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...any)
+	InfoContext(ctx context.Context, msg string, args ...any)
+	WarnContext(ctx context.Context, msg string, args ...any)
+	ErrorContext(ctx context.Context, msg string, args ...any)
+}
+
+// nopLogger is the default Logger, which discards everything.
+type nopLogger struct{}
+
+func (nopLogger) DebugContext(ctx context.Context, msg string, args ...any) {}
+func (nopLogger) InfoContext(ctx context.Context, msg string, args ...any)  {}
+func (nopLogger) WarnContext(ctx context.Context, msg string, args ...any)  {}
+func (nopLogger) ErrorContext(ctx context.Context, msg string, args ...any) {}
+
+const redacted = "REDACTED"
+
+// sensitiveHeaders are redacted from logged headers.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// sensitiveFields are redacted from logged query strings, forms and
+// JSON bodies. Names are compared case-insensitively.
+var sensitiveFields = map[string]bool{
+	"key":             true,
+	"password":        true,
+	"secret":          true,
+	"token":           true,
+	"access_token":    true,
+	"refresh_token":   true,
+	"oauth_signature": true,
+	"oauth_token":     true,
+}
+
+// redactHeader returns a copy of the header with any credentials
+// replaced, so that it is safe to log.
+func redactHeader(h http.Header) http.Header {
+	r := h.Clone()
+	for _, k := range sensitiveHeaders {
+		if _, ok := r[k]; ok {
+			r[k] = []string{redacted}
+		}
+	}
+	return r
+}
+
+// redactURL returns the URL as a string, with any sensitive
+// query parameters replaced.
+func redactURL(u *url.URL) string {
+	if len(u.RawQuery) == 0 {
+		return u.String()
+	}
+	r := *u
+	r.RawQuery = redactValues(u.Query()).Encode()
+	return r.String()
+}
+
+// redactValues returns a copy of the values with any sensitive
+// fields replaced.
+func redactValues(v url.Values) url.Values {
+	r := url.Values{}
+	for k, vals := range v {
+		if sensitiveFields[strings.ToLower(k)] {
+			r[k] = []string{redacted}
+		} else {
+			r[k] = vals
+		}
+	}
+	return r
+}
+
+// redactBody returns the encoded body of a request with any sensitive
+// fields replaced. Bodies that can't be parsed are summarised by size
+// rather than logged.
+func redactBody(values interface{}, body []byte) string {
+	if v, ok := values.(url.Values); ok {
+		return redactValues(v).Encode()
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+
+	out, err := json.Marshal(redactJSON(data))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	return string(out)
+}
+
+// redactJSON replaces sensitive fields, at any depth, in
+// unmarshalled JSON.
+func redactJSON(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if sensitiveFields[strings.ToLower(k)] {
+				v[k] = redacted
+			} else {
+				v[k] = redactJSON(val)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJSON(v[i])
+		}
+	}
+	return data
+}
@@ -0,0 +1,60 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func Test_Logger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"pk": 1}`))
+	}))
+	defer srv.Close()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c := New(&Anonymous{}, WithBaseURL(srv.URL), WithHTTPClient(srv.Client()), WithLogger(logger))
+	if _, err := c.Keys.Create(context.Background(), "marcus", "ssh-rsa AAAAB3NzaC1yc2E", "laptop"); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "label=laptop") {
+		t.Errorf("expected the request body to be logged; got %s", out)
+	}
+	if strings.Contains(out, "AAAAB3NzaC1yc2E") {
+		t.Errorf("expected the public key to be redacted; got %s", out)
+	}
+}
+
+func Test_Redact(t *testing.T) {
+	body := `{"name": "hook", "secret": "s3cr3t", "links": [{"token": "abc"}]}`
+	got := redactBody(map[string]string{}, []byte(body))
+	if strings.Contains(got, "s3cr3t") || strings.Contains(got, "abc") || !strings.Contains(got, "hook") {
+		t.Errorf("redacted body [%v]; expected secret and token to be redacted", got)
+	}
+
+	if got := redactBody(map[string]string{}, []byte("not json")); got != "[8 bytes]" {
+		t.Errorf("redacted body [%v]; want [%v]", got, "[8 bytes]")
+	}
+
+	u, _ := url.Parse("https://api.bitbucket.org/2.0/user?access_token=abc&q=1")
+	if got := redactURL(u); strings.Contains(got, "abc") || !strings.Contains(got, "q=1") {
+		t.Errorf("redacted url [%v]; expected access_token to be redacted", got)
+	}
+
+	h := http.Header{"Authorization": {`OAuth oauth_signature="xyz"`}, "Accept": {"*/*"}}
+	if got := redactHeader(h); got.Get("Authorization") != redacted || got.Get("Accept") != "*/*" {
+		t.Errorf("redacted header [%v]; expected Authorization to be redacted", got)
+	}
+	if h.Get("Authorization") == redacted {
+		t.Errorf("expected redactHeader not to modify the original header")
+	}
+}
@@ -0,0 +1,125 @@
+package bitbucket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// Middleware wraps the http.RoundTripper used by a Client, allowing
+// requests and responses to be observed or altered. Middleware sees every
+// attempt of a request, including retries.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to allow the use of ordinary functions
+// as an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(r).
+func (f RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// chain returns a copy of the http.Client with its transport wrapped in
+// the Middleware. The first Middleware is the outermost, and so sees the
+// request first and the response last.
+func chain(hc *http.Client, middleware []Middleware) *http.Client {
+	rt := hc.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	for i := len(middleware) - 1; i >= 0; i-- {
+		rt = middleware[i](rt)
+	}
+
+	wrapped := *hc
+	wrapped.Transport = rt
+	return &wrapped
+}
+
+// Logging is Middleware that logs each request and its outcome to the
+// Logger. Credentials in the request headers are redacted.
+func Logging(logger Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			ctx := r.Context()
+			logger.DebugContext(ctx, "bitbucket request",
+				"method", r.Method,
+				"url", redactURL(r.URL),
+				"header", redactHeader(r.Header),
+			)
+
+			start := time.Now()
+			resp, err := next.RoundTrip(r)
+			elapsed := time.Since(start)
+
+			if err != nil {
+				logger.ErrorContext(ctx, "bitbucket request failed",
+					"method", r.Method,
+					"url", redactURL(r.URL),
+					"duration", elapsed,
+					"error", err,
+				)
+				return resp, err
+			}
+
+			log := logger.InfoContext
+			if resp.StatusCode >= 400 {
+				log = logger.WarnContext
+			}
+			log(ctx, "bitbucket response",
+				"method", r.Method,
+				"url", redactURL(r.URL),
+				"status", resp.StatusCode,
+				"duration", elapsed,
+			)
+			return resp, nil
+		})
+	}
+}
+
+// Timing is Middleware that calls fn with the duration of each request,
+// for example to record it as a metric. The response is nil if the
+// request failed.
+func Timing(fn func(r *http.Request, resp *http.Response, elapsed time.Duration)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(r)
+			fn(r, resp, time.Since(start))
+			return resp, err
+		})
+	}
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a context carrying the request ID, which
+// the RequestID Middleware sends in place of a generated one. This is
+// useful for propagating the ID of an incoming request.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID is Middleware that sets the named header (ie X-Request-Id) to
+// the request ID found in the request's context, or to a randomly
+// generated ID if there is none.
+func RequestID(header string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			id, _ := r.Context().Value(requestIDKey{}).(string)
+			if len(id) == 0 {
+				b := make([]byte, 16)
+				rand.Read(b)
+				id = hex.EncodeToString(b)
+			}
+
+			// a RoundTripper must not modify the request
+			r = r.Clone(r.Context())
+			r.Header.Set(header, id)
+			return next.RoundTrip(r)
+		})
+	}
+}
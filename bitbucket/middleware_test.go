@@ -0,0 +1,88 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Middleware(t *testing.T) {
+	var requestID string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = r.Header.Get("X-Request-Id")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(r)
+			})
+		}
+	}
+
+	var timed time.Duration
+	timing := Timing(func(r *http.Request, resp *http.Response, elapsed time.Duration) {
+		timed = elapsed
+	})
+
+	c := New(&Anonymous{},
+		WithBaseURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+		WithMiddleware(trace("first"), trace("second")),
+		WithMiddleware(RequestID("X-Request-Id"), timing),
+	)
+
+	ctx := ContextWithRequestID(context.Background(), "abc123")
+	if _, err := c.Users.Current(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("middleware order [%v]; want [%v]", order, "first,second")
+	}
+	if requestID != "abc123" {
+		t.Errorf("request id [%v]; want [%v]", requestID, "abc123")
+	}
+	if timed == 0 {
+		t.Errorf("expected the Timing middleware to record a duration")
+	}
+
+	// a request ID is generated if none is in the context
+	if _, err := c.Users.Current(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(requestID) != 32 {
+		t.Errorf("expected a generated request id; got [%v]", requestID)
+	}
+}
+
+func Test_LoggingMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	auth := &BasicAuth{Username: "marcus", Password: "hunter2"}
+	c := New(auth, WithBaseURL(srv.URL), WithHTTPClient(srv.Client()), WithMiddleware(Logging(logger)))
+	c.Users.Current(context.Background())
+
+	out := buf.String()
+	if !strings.Contains(out, "status=404") {
+		t.Errorf("expected the response status to be logged; got %s", out)
+	}
+	if !strings.Contains(out, "REDACTED") || strings.Contains(out, "Basic ") {
+		t.Errorf("expected the Authorization header to be redacted; got %s", out)
+	}
+}
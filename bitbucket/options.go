@@ -0,0 +1,82 @@
+package bitbucket
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DefaultBaseURL is the root of the Bitbucket Cloud API. The API version
+// (ie /1.0) is appended to this value when requests are built.
+const DefaultBaseURL = "https://api.bitbucket.org"
+
+// Option configures a Client. Options are passed to New and applied
+// in order, so a later Option overrides an earlier one.
+type Option func(*Client)
+
+// WithBaseURL points the Client at a different API root, such as an
+// httptest.Server in unit tests. The URL should not include the API
+// version or a trailing slash.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the http.Client used to execute requests. This
+// allows each Client to carry its own transport, timeouts and proxy
+// settings.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHeader adds a header that is sent with every request. It may be
+// used more than once to add several headers, or several values for
+// the same header.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithRetryPolicy sets the RetryPolicy used by the Client. Requests are
+// not retried unless a RetryPolicy is set.
+func WithRetryPolicy(p *RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithRateLimiter limits the rate at which the Client sends requests.
+// The same RateLimiter may be given to several Clients so that they
+// share a single quota.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = l
+	}
+}
+
+// WithMiddleware adds Middleware to the Client's transport. It may be
+// used more than once, and Middleware is applied in the order given,
+// with the first being the outermost.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// WithLogger routes the Client's diagnostics to the Logger. Nothing
+// is logged by default.
+func WithLogger(l Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
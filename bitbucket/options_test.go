@@ -0,0 +1,54 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_Options(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{"pk": 1, "key": "ssh-rsa AAAA", "label": "test"}`))
+	}))
+	defer srv.Close()
+
+	c := New(&Anonymous{},
+		WithBaseURL(srv.URL+"/"),
+		WithHTTPClient(srv.Client()),
+		WithUserAgent("go-bitbucket-test"),
+		WithHeader("X-Test", "a"),
+		WithHeader("X-Test", "b"),
+	)
+
+	key, err := c.Keys.Find(context.Background(), "marcus", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Label != "test" {
+		t.Errorf("key label [%v]; want [%v]", key.Label, "test")
+	}
+	if got.URL.Path != "/1.0/users/marcus/ssh-keys/1" {
+		t.Errorf("request path [%v]; want [%v]", got.URL.Path, "/1.0/users/marcus/ssh-keys/1")
+	}
+	if ua := got.Header.Get("User-Agent"); ua != "go-bitbucket-test" {
+		t.Errorf("user agent [%v]; want [%v]", ua, "go-bitbucket-test")
+	}
+	if h := got.Header["X-Test"]; len(h) != 2 || h[0] != "a" || h[1] != "b" {
+		t.Errorf("default header [%v]; want [%v]", h, []string{"a", "b"})
+	}
+}
+
+func Test_OptionsDefaults(t *testing.T) {
+	c := New(&Anonymous{})
+
+	if c.baseURL != DefaultBaseURL {
+		t.Errorf("base url [%v]; want [%v]", c.baseURL, DefaultBaseURL)
+	}
+	if c.client() != DefaultClient {
+		t.Errorf("expected DefaultClient when no http.Client is configured")
+	}
+}
@@ -0,0 +1,173 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// ErrNoMorePages is returned by Iterator.NextPage once every page of a
+// listing has been fetched.
+var ErrNoMorePages = errors.New("no more pages")
+
+// ListOptions controls how a 2.0 listing is paginated.
+type ListOptions struct {
+	// The number of items requested per page. Zero uses the Bitbucket
+	// default (typically 10).
+	PageLen int
+
+	// The page to start from. Zero starts from the first page.
+	Page int
+
+	// The maximum number of items to return across all pages. Zero
+	// returns every item.
+	MaxItems int
+}
+
+// Page is a single page of a Bitbucket 2.0 paginated response.
+type Page[T any] struct {
+	// The items on this page.
+	Values []T `json:"values"`
+
+	// Links to the next and previous pages. Next is empty on
+	// the last page.
+	Next     string `json:"next"`
+	Previous string `json:"previous"`
+
+	// The page number, the number of items requested per page, and the
+	// total number of items across all pages. Bitbucket omits Page and
+	// Size on some listings, in which case they are zero.
+	Page    int `json:"page"`
+	PageLen int `json:"pagelen"`
+	Size    int `json:"size"`
+}
+
+// Iterator lazily walks the items of a Bitbucket 2.0 listing, fetching
+// each page only when the previous one has been consumed.
+//
+//	it := client.PullRequests.List(ctx, owner, slug, nil)
+//	for it.Next() {
+//		pr := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// An Iterator is not safe for concurrent use.
+type Iterator[T any] struct {
+	ctx    context.Context
+	client *Client
+
+	// url of the next page to fetch, and the query parameters to send
+	// with it (only the first page needs parameters, Bitbucket includes
+	// them in the next link)
+	next   string
+	params url.Values
+
+	max   int
+	count int
+
+	values []T
+	value  T
+	err    error
+}
+
+// newIterator creates an Iterator over the 2.0 listing at path.
+func newIterator[T any](ctx context.Context, c *Client, path string, params url.Values, opts *ListOptions) *Iterator[T] {
+	it := &Iterator[T]{
+		ctx:    ctx,
+		client: c,
+		next:   c.baseURL + "/2.0" + path,
+		params: url.Values{},
+	}
+
+	for k, v := range params {
+		it.params[k] = v
+	}
+
+	if opts != nil {
+		if opts.PageLen > 0 {
+			it.params.Set("pagelen", strconv.Itoa(opts.PageLen))
+		}
+		if opts.Page > 0 {
+			it.params.Set("page", strconv.Itoa(opts.Page))
+		}
+		it.max = opts.MaxItems
+	}
+
+	return it
+}
+
+// Next advances the Iterator to the next item, fetching the next page
+// if required. It returns false when there are no more items or an
+// error occurred.
+func (it *Iterator[T]) Next() bool {
+	for len(it.values) == 0 {
+		page, err := it.NextPage()
+		if err != nil {
+			if err != ErrNoMorePages {
+				it.err = err
+			}
+			return false
+		}
+		it.values = page.Values
+	}
+
+	it.value = it.values[0]
+	it.values = it.values[1:]
+	return true
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the first error encountered by Next.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// NextPage fetches the next page of the listing, returning ErrNoMorePages
+// once the listing is exhausted or MaxItems has been reached. It should not
+// be mixed with calls to Next.
+func (it *Iterator[T]) NextPage() (*Page[T], error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+	if len(it.next) == 0 || (it.max > 0 && it.count >= it.max) {
+		return nil, ErrNoMorePages
+	}
+
+	page := Page[T]{}
+	if err := it.client.doURL(it.ctx, "GET", it.next, it.params, nil, &page); err != nil {
+		it.err = err
+		return nil, err
+	}
+
+	it.next = page.Next
+	it.params = nil
+
+	// trim the page so we don't exceed MaxItems
+	if it.max > 0 && it.count+len(page.Values) > it.max {
+		page.Values = page.Values[:it.max-it.count]
+	}
+	it.count += len(page.Values)
+
+	return &page, nil
+}
+
+// All consumes the remaining items of the Iterator and returns them
+// as a slice.
+func (it *Iterator[T]) All() ([]T, error) {
+	all := []T{}
+	for it.Next() {
+		all = append(all, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
@@ -0,0 +1,116 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+type testItem struct {
+	Id int `json:"id"`
+}
+
+// newPagesServer serves 25 items, paginated according to the
+// request's pagelen and page parameters.
+func newPagesServer(t *testing.T, requests *int) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.URL.Path != "/2.0/items" {
+			t.Errorf("request path [%v]; want [%v]", r.URL.Path, "/2.0/items")
+		}
+
+		pagelen, _ := strconv.Atoi(r.URL.Query().Get("pagelen"))
+		if pagelen == 0 {
+			pagelen = 10
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+
+		const size = 25
+		values := ""
+		for i := (page - 1) * pagelen; i < page*pagelen && i < size; i++ {
+			if len(values) != 0 {
+				values += ","
+			}
+			values += fmt.Sprintf(`{"id": %d}`, i)
+		}
+
+		next := ""
+		if page*pagelen < size {
+			next = fmt.Sprintf(`"next": "%s/2.0/items?pagelen=%d&page=%d",`, srv.URL, pagelen, page+1)
+		}
+		fmt.Fprintf(w, `{"pagelen": %d, "page": %d, "size": %d, %s "values": [%s]}`, pagelen, page, size, next, values)
+	}))
+	return srv
+}
+
+func Test_Iterator(t *testing.T) {
+	requests := 0
+	srv := newPagesServer(t, &requests)
+	defer srv.Close()
+
+	c := New(&Anonymous{}, WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+	ctx := context.Background()
+
+	// ALL items, across every page
+	items, err := newIterator[*testItem](ctx, c, "/items", nil, nil).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 25 {
+		t.Errorf("item count [%v]; want [%v]", len(items), 25)
+	}
+	for i, item := range items {
+		if item.Id != i {
+			t.Errorf("item [%v] id [%v]; want [%v]", i, item.Id, i)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("request count [%v]; want [%v]", requests, 3)
+	}
+
+	// MAX items stops fetching pages early
+	requests = 0
+	items, err = newIterator[*testItem](ctx, c, "/items", nil, &ListOptions{PageLen: 5, MaxItems: 7}).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 7 {
+		t.Errorf("item count [%v]; want [%v]", len(items), 7)
+	}
+	if requests != 2 {
+		t.Errorf("request count [%v]; want [%v]", requests, 2)
+	}
+}
+
+func Test_IteratorNextPage(t *testing.T) {
+	requests := 0
+	srv := newPagesServer(t, &requests)
+	defer srv.Close()
+
+	c := New(&Anonymous{}, WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+	it := newIterator[*testItem](context.Background(), c, "/items", nil, &ListOptions{PageLen: 20, Page: 2})
+
+	// FETCH a single page
+	page, err := it.NextPage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Page != 2 || page.Size != 25 || len(page.Values) != 5 {
+		t.Errorf("page [%v] size [%v] values [%v]; want [%v] [%v] [%v]", page.Page, page.Size, len(page.Values), 2, 25, 5)
+	}
+	if page.Values[0].Id != 20 {
+		t.Errorf("first item id [%v]; want [%v]", page.Values[0].Id, 20)
+	}
+
+	// there is no page after the last one
+	if _, err := it.NextPage(); err != ErrNoMorePages {
+		t.Errorf("expected error [%v]; got [%v]", ErrNoMorePages, err)
+	}
+}
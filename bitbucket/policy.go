@@ -0,0 +1,333 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BranchPolicy is the complete set of branch restrictions a repository
+// should have. It can be loaded from JSON or YAML, ie:
+//
+//	rules:
+//	  - kind: push
+//	    pattern: master
+//	    users: ["{a1b2c3d4-...}"]
+//	    groups: [developers]
+//	  - kind: require_approvals_to_merge
+//	    pattern: master
+//	    value: 2
+//	  - kind: delete
+//	    branch_type: production
+type BranchPolicy struct {
+	Rules []*BranchRule `json:"rules" yaml:"rules"`
+}
+
+// BranchRule is a single branch restriction in a BranchPolicy.
+type BranchRule struct {
+	// One of the RestrictionKind constants.
+	Kind string `json:"kind" yaml:"kind"`
+
+	// The glob matching the names of the branches restricted. Ignored
+	// if BranchType is set.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+
+	// One of the BranchType constants, to restrict the branches of that
+	// type in the repository's branching model rather than by name.
+	BranchType string `json:"branch_type,omitempty" yaml:"branch_type,omitempty"`
+
+	// The number of approvals or builds required, for the kinds of
+	// restriction that need one.
+	Value int `json:"value,omitempty" yaml:"value,omitempty"`
+
+	// The users exempt from the restriction, identified by UUID (ie
+	// "{a1b2c3d4-...}") or username, and the groups, identified by slug.
+	Users  []string `json:"users,omitempty" yaml:"users,omitempty"`
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+// restriction converts the rule to the branch restriction it describes.
+func (b *BranchRule) restriction() *BranchRestriction {
+	r := &BranchRestriction{
+		Kind:            b.Kind,
+		BranchMatchKind: BranchMatchKindGlob,
+		Pattern:         b.Pattern,
+		Value:           b.Value,
+	}
+	if len(b.BranchType) != 0 {
+		r.BranchMatchKind = BranchMatchKindBranchingModel
+		r.BranchType = b.BranchType
+		r.Pattern = ""
+	}
+	for _, u := range b.Users {
+		if strings.HasPrefix(u, "{") {
+			r.Users = append(r.Users, &User{UUID: u})
+		} else {
+			r.Users = append(r.Users, &User{Username: u})
+		}
+	}
+	for _, g := range b.Groups {
+		r.Groups = append(r.Groups, &Group{Slug: g})
+	}
+	return r
+}
+
+// The actions in a PolicyPlan.
+const (
+	PolicyActionCreate = "create"
+	PolicyActionUpdate = "update"
+	PolicyActionDelete = "delete"
+)
+
+// PolicyChange is a change needed to bring a repository's branch
+// restrictions in line with a BranchPolicy.
+type PolicyChange struct {
+	// One of the PolicyAction constants.
+	Action string
+
+	// The restriction as it is now, nil when creating, and as it should
+	// be, nil when deleting.
+	Current *BranchRestriction
+	Desired *BranchRestriction
+}
+
+// String describes the change, ie "~ require_approvals_to_merge on
+// master (value: 1 -> 2)".
+func (c *PolicyChange) String() string {
+	switch c.Action {
+	case PolicyActionCreate:
+		return fmt.Sprintf("+ %s%s", describeRestriction(c.Desired), describeExemptions(c.Desired))
+	case PolicyActionDelete:
+		return fmt.Sprintf("- %s", describeRestriction(c.Current))
+	}
+
+	var diffs []string
+	if c.Current.Value != c.Desired.Value {
+		diffs = append(diffs, fmt.Sprintf("value: %v -> %v", c.Current.Value, c.Desired.Value))
+	}
+	if cur, want := userKeys(c.Current.Users), userKeys(c.Desired.Users); !sameKeys(cur, want) {
+		diffs = append(diffs, fmt.Sprintf("users: [%s] -> [%s]", strings.Join(cur, ", "), strings.Join(want, ", ")))
+	}
+	if cur, want := groupKeys(c.Current.Groups), groupKeys(c.Desired.Groups); !sameKeys(cur, want) {
+		diffs = append(diffs, fmt.Sprintf("groups: [%s] -> [%s]", strings.Join(cur, ", "), strings.Join(want, ", ")))
+	}
+	return fmt.Sprintf("~ %s (%s)", describeRestriction(c.Desired), strings.Join(diffs, "; "))
+}
+
+// PolicyPlan is the set of changes needed to bring a repository's branch
+// restrictions in line with a BranchPolicy.
+type PolicyPlan struct {
+	Owner   string
+	Slug    string
+	Changes []*PolicyChange
+}
+
+// Empty reports whether the repository already matches the policy.
+func (p *PolicyPlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String describes the plan, one change per line, for printing before
+// the plan is applied.
+func (p *PolicyPlan) String() string {
+	if p.Empty() {
+		return fmt.Sprintf("%s/%s: no changes\n", p.Owner, p.Slug)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s/%s: %d changes\n", p.Owner, p.Slug, len(p.Changes))
+	for _, c := range p.Changes {
+		fmt.Fprintf(&b, "  %s\n", c)
+	}
+	return b.String()
+}
+
+// Plan compares the policy with the branch restrictions the repository
+// currently has, and returns the changes needed to make them match.
+// Restrictions the policy doesn't mention are deleted.
+func (r *BranchRestrictionResource) Plan(ctx context.Context, owner, slug string, policy *BranchPolicy) (*PolicyPlan, error) {
+	current, err := r.List(ctx, owner, slug, nil).All()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string][]*BranchRestriction{}
+	for _, c := range current {
+		key := restrictionKey(c)
+		existing[key] = append(existing[key], c)
+	}
+
+	plan := &PolicyPlan{Owner: owner, Slug: slug}
+	seen := map[string]bool{}
+	for _, rule := range policy.Rules {
+		desired := rule.restriction()
+		key := restrictionKey(desired)
+		if seen[key] {
+			return nil, fmt.Errorf("policy has more than one rule for %s", describeRestriction(desired))
+		}
+		seen[key] = true
+
+		// the restriction doesn't exist, so we should create it
+		matches := existing[key]
+		if len(matches) == 0 {
+			plan.Changes = append(plan.Changes, &PolicyChange{Action: PolicyActionCreate, Desired: desired})
+			continue
+		}
+
+		// otherwise we should update it, if it is different, and
+		// remove any duplicates
+		found := matches[0]
+		existing[key] = matches[1:]
+		if !sameRestriction(found, desired) {
+			desired.Id = found.Id
+			plan.Changes = append(plan.Changes, &PolicyChange{Action: PolicyActionUpdate, Current: found, Desired: desired})
+		}
+	}
+
+	for _, c := range current {
+		for _, extra := range existing[restrictionKey(c)] {
+			if extra == c {
+				plan.Changes = append(plan.Changes, &PolicyChange{Action: PolicyActionDelete, Current: c})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// Apply brings the repository's branch restrictions in line with the
+// policy, creating, updating and deleting only the restrictions that
+// differ. If dryRun is true, the plan is returned without being applied.
+//
+// If a change fails, the changes before it remain applied and the plan
+// is returned along with the error.
+func (r *BranchRestrictionResource) Apply(ctx context.Context, owner, slug string, policy *BranchPolicy, dryRun bool) (*PolicyPlan, error) {
+	plan, err := r.Plan(ctx, owner, slug, policy)
+	if err != nil || dryRun {
+		return plan, err
+	}
+
+	for _, c := range plan.Changes {
+		switch c.Action {
+		case PolicyActionCreate:
+			_, err = r.Create(ctx, owner, slug, c.Desired)
+		case PolicyActionUpdate:
+			_, err = r.Update(ctx, owner, slug, c.Desired)
+		case PolicyActionDelete:
+			err = r.Delete(ctx, owner, slug, c.Current.Id)
+		}
+		if err != nil {
+			return plan, fmt.Errorf("%s: %w", c, err)
+		}
+	}
+
+	return plan, nil
+}
+
+// restrictionKey identifies a restriction by its kind and the branches
+// it applies to. A repository has at most one restriction per key.
+func restrictionKey(r *BranchRestriction) string {
+	if r.BranchMatchKind == BranchMatchKindBranchingModel {
+		return r.Kind + " type:" + r.BranchType
+	}
+	return r.Kind + " glob:" + r.Pattern
+}
+
+// describeRestriction describes a restriction by its kind and the
+// branches it applies to, ie "push on master".
+func describeRestriction(r *BranchRestriction) string {
+	if r.BranchMatchKind == BranchMatchKindBranchingModel {
+		return fmt.Sprintf("%s on %s branches", r.Kind, r.BranchType)
+	}
+	return fmt.Sprintf("%s on %s", r.Kind, r.Pattern)
+}
+
+// describeExemptions describes the value and exemptions of a new
+// restriction, if it has any.
+func describeExemptions(r *BranchRestriction) string {
+	var parts []string
+	if r.Value != 0 {
+		parts = append(parts, fmt.Sprintf("value: %v", r.Value))
+	}
+	if len(r.Users) != 0 {
+		parts = append(parts, fmt.Sprintf("users: [%s]", strings.Join(userKeys(r.Users), ", ")))
+	}
+	if len(r.Groups) != 0 {
+		parts = append(parts, fmt.Sprintf("groups: [%s]", strings.Join(groupKeys(r.Groups), ", ")))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, "; ") + ")"
+}
+
+// sameRestriction reports whether the current restriction already
+// matches the desired one.
+func sameRestriction(current, desired *BranchRestriction) bool {
+	if current.Value != desired.Value {
+		return false
+	}
+	if !sameKeys(groupKeys(current.Groups), groupKeys(desired.Groups)) {
+		return false
+	}
+
+	// the desired users may be identified by UUID or by username,
+	// so each must match one of the current users either way
+	if len(current.Users) != len(desired.Users) {
+		return false
+	}
+	for _, want := range desired.Users {
+		found := false
+		for _, u := range current.Users {
+			if (len(want.UUID) != 0 && want.UUID == u.UUID) ||
+				(len(want.Username) != 0 && (want.Username == u.Username || want.Username == u.Nickname)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// userKeys gets the sorted UUIDs, or usernames, of the users.
+func userKeys(users []*User) []string {
+	keys := []string{}
+	for _, u := range users {
+		switch {
+		case len(u.UUID) != 0:
+			keys = append(keys, u.UUID)
+		case len(u.Username) != 0:
+			keys = append(keys, u.Username)
+		default:
+			keys = append(keys, u.Nickname)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// groupKeys gets the sorted slugs of the groups.
+func groupKeys(groups []*Group) []string {
+	keys := []string{}
+	for _, g := range groups {
+		keys = append(keys, g.Slug)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sameKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_BranchPolicy(t *testing.T) {
+	var changes []string
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(samplePolicyRestrictions))
+			return
+		}
+		changes = append(changes, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{}`))
+	})
+
+	policy := BranchPolicy{}
+	if err := json.Unmarshal([]byte(samplePolicy), &policy); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// a dry run should only plan the changes
+	plan, err := c.BranchRestrictions.Apply(ctx, "marcus", "project-x", &policy, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected a dry run to make no changes; got %v", changes)
+	}
+
+	want := []string{
+		"~ require_approvals_to_merge on master (value: 1 -> 2)",
+		"+ delete on production branches",
+		"- force on develop",
+		"- push on master",
+	}
+	if len(plan.Changes) != len(want) {
+		t.Fatalf("plan:\n%v", plan)
+	}
+	for i, change := range plan.Changes {
+		if change.String() != want[i] {
+			t.Errorf("change [%v]; want [%v]", change, want[i])
+		}
+	}
+	if !strings.HasPrefix(plan.String(), "marcus/project-x: 4 changes\n") {
+		t.Errorf("plan:\n%v", plan)
+	}
+
+	// applying the plan should only change what differs
+	if _, err := c.BranchRestrictions.Apply(ctx, "marcus", "project-x", &policy, false); err != nil {
+		t.Fatal(err)
+	}
+	wantChanges := []string{
+		"PUT /2.0/repositories/marcus/project-x/branch-restrictions/2",
+		"POST /2.0/repositories/marcus/project-x/branch-restrictions",
+		"DELETE /2.0/repositories/marcus/project-x/branch-restrictions/3",
+		"DELETE /2.0/repositories/marcus/project-x/branch-restrictions/4",
+	}
+	if strings.Join(changes, "\n") != strings.Join(wantChanges, "\n") {
+		t.Errorf("changes %v; want %v", changes, wantChanges)
+	}
+}
+
+func Test_BranchPolicyDuplicateRule(t *testing.T) {
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"values": []}`))
+	})
+
+	policy := &BranchPolicy{Rules: []*BranchRule{
+		{Kind: RestrictionKindDelete, Pattern: "master"},
+		{Kind: RestrictionKindDelete, Pattern: "master"},
+	}}
+	if _, err := c.BranchRestrictions.Plan(context.Background(), "marcus", "project-x", policy); err == nil {
+		t.Errorf("expected an error for a policy with duplicate rules")
+	}
+}
+
+var samplePolicy = `
+{
+    "rules": [
+        {"kind": "push", "pattern": "master", "users": ["{a1b2}", "jane"], "groups": ["developers"]},
+        {"kind": "require_approvals_to_merge", "pattern": "master", "value": 2},
+        {"kind": "delete", "branch_type": "production"}
+    ]
+}
+`
+
+var samplePolicyRestrictions = `
+{
+    "values": [
+        {
+            "id": 1, "kind": "push", "branch_match_kind": "glob", "pattern": "master",
+            "users": [{"uuid": "{c3d4}", "nickname": "jane"}, {"uuid": "{a1b2}", "nickname": "marcus"}],
+            "groups": [{"slug": "developers"}]
+        },
+        {"id": 2, "kind": "require_approvals_to_merge", "branch_match_kind": "glob", "pattern": "master", "value": 1},
+        {"id": 3, "kind": "force", "branch_match_kind": "glob", "pattern": "develop"},
+        {"id": 4, "kind": "push", "branch_match_kind": "glob", "pattern": "master"}
+    ]
+}
+`
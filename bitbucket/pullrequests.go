@@ -0,0 +1,349 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// The states of a pull request.
+const (
+	PullRequestStateOpen       = "OPEN"
+	PullRequestStateMerged     = "MERGED"
+	PullRequestStateDeclined   = "DECLINED"
+	PullRequestStateSuperseded = "SUPERSEDED"
+)
+
+// The strategies that may be used to merge a pull request.
+const (
+	MergeStrategyMergeCommit = "merge_commit"
+	MergeStrategySquash      = "squash"
+	MergeStrategyFastForward = "fast_forward"
+)
+
+type PullRequest struct {
+	Id          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+
+	// One of the PullRequestState constants.
+	State string `json:"state"`
+
+	Author      *User                `json:"author"`
+	Source      *PullRequestEndpoint `json:"source"`
+	Destination *PullRequestEndpoint `json:"destination"`
+
+	// The commit created when the pull request was merged, and the user
+	// and reason given when it was merged or declined.
+	MergeCommit *CommitRef `json:"merge_commit"`
+	ClosedBy    *User      `json:"closed_by"`
+	Reason      string     `json:"reason"`
+
+	// Indicates the source branch is deleted once the pull
+	// request is merged.
+	CloseSourceBranch bool `json:"close_source_branch"`
+
+	Reviewers    []*User        `json:"reviewers"`
+	Participants []*Participant `json:"participants"`
+
+	CommentCount int       `json:"comment_count"`
+	TaskCount    int       `json:"task_count"`
+	CreatedOn    time.Time `json:"created_on"`
+	UpdatedOn    time.Time `json:"updated_on"`
+	Links        *Links    `json:"links"`
+}
+
+// PullRequestEndpoint is the source or destination of a pull request.
+type PullRequestEndpoint struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Commit     *CommitRef `json:"commit"`
+	Repository *RepoRef   `json:"repository"`
+}
+
+// CommitRef is a reference to a commit, as embedded in other
+// 2.0 resources.
+type CommitRef struct {
+	Hash  string `json:"hash"`
+	Links *Links `json:"links,omitempty"`
+}
+
+// RepoRef is a reference to a repository, as embedded in other
+// 2.0 resources.
+type RepoRef struct {
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	UUID     string `json:"uuid"`
+	Links    *Links `json:"links,omitempty"`
+}
+
+// Participant is a user who has reviewed or commented on
+// a pull request.
+type Participant struct {
+	User *User `json:"user"`
+
+	// Either PARTICIPANT or REVIEWER.
+	Role string `json:"role"`
+
+	Approved bool `json:"approved"`
+
+	// Either "approved", "changes_requested" or empty.
+	State string `json:"state"`
+
+	ParticipatedOn *time.Time `json:"participated_on"`
+}
+
+// PullRequestOptions describes a pull request to create, or the changes
+// to make to an existing pull request.
+type PullRequestOptions struct {
+	Title       string
+	Description string
+
+	// The branch to merge from, and the full name (owner/slug) of the
+	// repository it belongs to when opening a pull request from a fork.
+	SourceBranch string
+	SourceRepo   string
+
+	// The branch to merge into. The repository's main branch is
+	// used if empty.
+	DestinationBranch string
+
+	// The UUIDs of the users to request reviews from.
+	Reviewers []string
+
+	// Delete the source branch once the pull request is merged.
+	CloseSourceBranch bool
+}
+
+// body converts the options to the JSON structure expected by
+// Bitbucket, omitting any fields that are not set.
+func (o *PullRequestOptions) body() map[string]interface{} {
+	body := map[string]interface{}{}
+	if o == nil {
+		return body
+	}
+	if len(o.Title) != 0 {
+		body["title"] = o.Title
+	}
+	if len(o.Description) != 0 {
+		body["description"] = o.Description
+	}
+	if len(o.SourceBranch) != 0 {
+		source := map[string]interface{}{
+			"branch": map[string]string{"name": o.SourceBranch},
+		}
+		if len(o.SourceRepo) != 0 {
+			source["repository"] = map[string]string{"full_name": o.SourceRepo}
+		}
+		body["source"] = source
+	}
+	if len(o.DestinationBranch) != 0 {
+		body["destination"] = map[string]interface{}{
+			"branch": map[string]string{"name": o.DestinationBranch},
+		}
+	}
+	if o.Reviewers != nil {
+		reviewers := []map[string]string{}
+		for _, uuid := range o.Reviewers {
+			reviewers = append(reviewers, map[string]string{"uuid": uuid})
+		}
+		body["reviewers"] = reviewers
+	}
+	if o.CloseSourceBranch {
+		body["close_source_branch"] = true
+	}
+	return body
+}
+
+// PullRequestListOptions filters and paginates a listing of
+// pull requests.
+type PullRequestListOptions struct {
+	ListOptions
+
+	// Only list pull requests in these states. Bitbucket lists
+	// only OPEN pull requests if no states are given.
+	States []string
+}
+
+// MergeOptions controls how a pull request is merged.
+type MergeOptions struct {
+	// One of the MergeStrategy constants. The repository's default
+	// strategy is used if empty.
+	Strategy string
+
+	// The commit message. Bitbucket generates one if empty.
+	Message string
+
+	// Delete the source branch once merged.
+	CloseSourceBranch bool
+}
+
+// Use the pullrequests resource to manage the pull requests of a
+// repository. This resource uses the 2.0 API.
+//
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/
+type PullRequestResource struct {
+	client *Client
+}
+
+// Gets the pull requests of a repository, optionally filtered by state.
+func (r *PullRequestResource) List(ctx context.Context, owner, slug string, opts *PullRequestListOptions) *Iterator[*PullRequest] {
+	params := url.Values{}
+	var listOpts *ListOptions
+	if opts != nil {
+		for _, state := range opts.States {
+			params.Add("state", state)
+		}
+		listOpts = &opts.ListOptions
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests", owner, slug)
+	return newIterator[*PullRequest](ctx, r.client, path, params, listOpts)
+}
+
+// Gets the pull request with the given id.
+func (r *PullRequestResource) Find(ctx context.Context, owner, slug string, id int) (*PullRequest, error) {
+	pr := PullRequest{}
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v", owner, slug, id)
+
+	if err := r.client.do2(ctx, "GET", path, nil, nil, &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// Creates a pull request from the source branch to the
+// destination branch.
+func (r *PullRequestResource) Create(ctx context.Context, owner, slug string, opts *PullRequestOptions) (*PullRequest, error) {
+	pr := PullRequest{}
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests", owner, slug)
+
+	if err := r.client.do2(ctx, "POST", path, nil, opts.body(), &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// Updates the pull request. Only the options that are set are
+// changed, such as the title and description.
+func (r *PullRequestResource) Update(ctx context.Context, owner, slug string, id int, opts *PullRequestOptions) (*PullRequest, error) {
+	pr := PullRequest{}
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v", owner, slug, id)
+
+	if err := r.client.do2(ctx, "PUT", path, nil, opts.body(), &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// Merges the pull request. The options may be nil, in which case the
+// repository's default merge strategy is used.
+func (r *PullRequestResource) Merge(ctx context.Context, owner, slug string, id int, opts *MergeOptions) (*PullRequest, error) {
+	body := map[string]interface{}{}
+	if opts != nil {
+		if len(opts.Strategy) != 0 {
+			body["merge_strategy"] = opts.Strategy
+		}
+		if len(opts.Message) != 0 {
+			body["message"] = opts.Message
+		}
+		if opts.CloseSourceBranch {
+			body["close_source_branch"] = true
+		}
+	}
+
+	pr := PullRequest{}
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/merge", owner, slug, id)
+	if err := r.client.do2(ctx, "POST", path, nil, body, &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// Declines the pull request.
+func (r *PullRequestResource) Decline(ctx context.Context, owner, slug string, id int) (*PullRequest, error) {
+	pr := PullRequest{}
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/decline", owner, slug, id)
+
+	if err := r.client.do2(ctx, "POST", path, nil, nil, &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// Approves the pull request as the authenticated user.
+func (r *PullRequestResource) Approve(ctx context.Context, owner, slug string, id int) (*Participant, error) {
+	p := Participant{}
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/approve", owner, slug, id)
+
+	if err := r.client.do2(ctx, "POST", path, nil, nil, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// Removes the authenticated user's approval of the pull request.
+func (r *PullRequestResource) Unapprove(ctx context.Context, owner, slug string, id int) error {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/approve", owner, slug, id)
+	return r.client.do2(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// Requests changes to the pull request as the authenticated user.
+func (r *PullRequestResource) RequestChanges(ctx context.Context, owner, slug string, id int) (*Participant, error) {
+	p := Participant{}
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/request-changes", owner, slug, id)
+
+	if err := r.client.do2(ctx, "POST", path, nil, nil, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// Removes the authenticated user's request for changes to
+// the pull request.
+func (r *PullRequestResource) UnrequestChanges(ctx context.Context, owner, slug string, id int) error {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/request-changes", owner, slug, id)
+	return r.client.do2(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// Gets the unified diff of the pull request. The caller must close the
+// returned reader. Use Diff to read the whole diff into a string.
+func (r *PullRequestResource) DiffReader(ctx context.Context, owner, slug string, id int) (io.ReadCloser, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/diff", owner, slug, id)
+	return r.client.stream2(ctx, path, nil)
+}
+
+// Gets the unified diff of the pull request.
+func (r *PullRequestResource) Diff(ctx context.Context, owner, slug string, id int) (string, error) {
+	return readString(r.DiffReader(ctx, owner, slug, id))
+}
+
+// Gets the patch series of the pull request, with one patch per
+// commit. The caller must close the returned reader. Use Patch to read
+// the whole patch into a string.
+func (r *PullRequestResource) PatchReader(ctx context.Context, owner, slug string, id int) (io.ReadCloser, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/patch", owner, slug, id)
+	return r.client.stream2(ctx, path, nil)
+}
+
+// Gets the patch series of the pull request.
+func (r *PullRequestResource) Patch(ctx context.Context, owner, slug string, id int) (string, error) {
+	return readString(r.PatchReader(ctx, owner, slug, id))
+}
+
+// Gets the lines added and removed for each file changed by
+// the pull request.
+func (r *PullRequestResource) Diffstat(ctx context.Context, owner, slug string, id int, opts *ListOptions) *Iterator[*DiffStat] {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%v/diffstat", owner, slug, id)
+	return newIterator[*DiffStat](ctx, r.client, path, nil, opts)
+}
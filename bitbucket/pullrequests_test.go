@@ -0,0 +1,309 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_PullRequestsList(t *testing.T) {
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2.0/repositories/marcus/project-x/pullrequests" {
+			t.Errorf("request path [%v]; want [%v]", r.URL.Path, "/2.0/repositories/marcus/project-x/pullrequests")
+		}
+		if states := r.URL.Query()["state"]; len(states) != 2 || states[0] != "OPEN" || states[1] != "MERGED" {
+			t.Errorf("state filter [%v]; want [%v]", states, []string{"OPEN", "MERGED"})
+		}
+		w.Write([]byte(samplePullRequests))
+	})
+
+	opts := &PullRequestListOptions{States: []string{PullRequestStateOpen, PullRequestStateMerged}}
+	prs, err := c.PullRequests.List(context.Background(), "marcus", "project-x", opts).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prs) != 1 {
+		t.Fatalf("pull request count [%v]; want [%v]", len(prs), 1)
+	}
+	pr := prs[0]
+	if pr.Id != 7 || pr.State != PullRequestStateOpen {
+		t.Errorf("pull request id [%v] state [%v]; want [%v] [%v]", pr.Id, pr.State, 7, PullRequestStateOpen)
+	}
+	if pr.Source.Branch.Name != "feature" || pr.Destination.Branch.Name != "master" {
+		t.Errorf("branches [%v] -> [%v]; want [%v] -> [%v]", pr.Source.Branch.Name, pr.Destination.Branch.Name, "feature", "master")
+	}
+	if pr.Source.Commit.Hash != "620ade18607a" || pr.Source.Repository.FullName != "marcus/project-x" {
+		t.Errorf("source commit [%v] repo [%v]", pr.Source.Commit.Hash, pr.Source.Repository.FullName)
+	}
+	if pr.Author.UUID != "{a1b2}" || pr.Author.DisplayName != "Marcus Bertrand" {
+		t.Errorf("author [%v] [%v]", pr.Author.UUID, pr.Author.DisplayName)
+	}
+	if len(pr.Participants) != 1 || !pr.Participants[0].Approved {
+		t.Errorf("expected one approving participant; got %v", pr.Participants)
+	}
+	if pr.CreatedOn.Year() != 2012 {
+		t.Errorf("created on [%v]; want year [%v]", pr.CreatedOn, 2012)
+	}
+}
+
+func Test_PullRequestsCreate(t *testing.T) {
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request [%v] [%v]; want [%v] [%v]", r.Method, r.Header.Get("Content-Type"), "POST", "application/json")
+		}
+
+		body := decodeBody(t, r)
+		if body["title"] != "Add feature" {
+			t.Errorf("title [%v]; want [%v]", body["title"], "Add feature")
+		}
+		source, _ := body["source"].(map[string]interface{})
+		if branch, ok := source["branch"].(map[string]interface{}); !ok || branch["name"] != "feature" {
+			t.Errorf("source [%v]; want branch [%v]", source, "feature")
+		}
+		if _, ok := body["destination"]; ok {
+			t.Errorf("expected no destination; got %v", body["destination"])
+		}
+		reviewers, _ := body["reviewers"].([]interface{})
+		if len(reviewers) != 1 {
+			t.Errorf("reviewers [%v]; want [%v]", reviewers, "{c3d4}")
+		} else if reviewer, ok := reviewers[0].(map[string]interface{}); !ok || reviewer["uuid"] != "{c3d4}" {
+			t.Errorf("reviewers [%v]; want [%v]", reviewers, "{c3d4}")
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 8, "title": "Add feature", "state": "OPEN"}`))
+	})
+
+	pr, err := c.PullRequests.Create(context.Background(), "marcus", "project-x", &PullRequestOptions{
+		Title:        "Add feature",
+		SourceBranch: "feature",
+		Reviewers:    []string{"{c3d4}"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Id != 8 {
+		t.Errorf("pull request id [%v]; want [%v]", pr.Id, 8)
+	}
+}
+
+func Test_PullRequestsNilOptions(t *testing.T) {
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if body := decodeBody(t, r); len(body) != 0 {
+			t.Errorf("body [%v]; want an empty body", body)
+		}
+		w.Write([]byte(`{"id": 8}`))
+	})
+	ctx := context.Background()
+
+	if _, err := c.PullRequests.Create(ctx, "marcus", "project-x", nil); err != nil {
+		t.Error(err)
+	}
+	if _, err := c.PullRequests.Update(ctx, "marcus", "project-x", 8, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_PullRequestsActions(t *testing.T) {
+	var requests []string
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/2.0/repositories/marcus/project-x/pullrequests/7/merge" {
+			body := decodeBody(t, r)
+			if body["merge_strategy"] != MergeStrategySquash {
+				t.Errorf("merge strategy [%v]; want [%v]", body["merge_strategy"], MergeStrategySquash)
+			}
+		}
+		if r.Method == "DELETE" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"id": 7, "approved": true}`))
+	})
+
+	ctx := context.Background()
+	if _, err := c.PullRequests.Update(ctx, "marcus", "project-x", 7, &PullRequestOptions{Title: "New title"}); err != nil {
+		t.Error(err)
+	}
+	if _, err := c.PullRequests.Merge(ctx, "marcus", "project-x", 7, &MergeOptions{Strategy: MergeStrategySquash}); err != nil {
+		t.Error(err)
+	}
+	if _, err := c.PullRequests.Decline(ctx, "marcus", "project-x", 7); err != nil {
+		t.Error(err)
+	}
+	if p, err := c.PullRequests.Approve(ctx, "marcus", "project-x", 7); err != nil || !p.Approved {
+		t.Errorf("expected approval; got %v %v", p, err)
+	}
+	if err := c.PullRequests.Unapprove(ctx, "marcus", "project-x", 7); err != nil {
+		t.Error(err)
+	}
+	if _, err := c.PullRequests.RequestChanges(ctx, "marcus", "project-x", 7); err != nil {
+		t.Error(err)
+	}
+	if err := c.PullRequests.UnrequestChanges(ctx, "marcus", "project-x", 7); err != nil {
+		t.Error(err)
+	}
+
+	want := []string{
+		"PUT /2.0/repositories/marcus/project-x/pullrequests/7",
+		"POST /2.0/repositories/marcus/project-x/pullrequests/7/merge",
+		"POST /2.0/repositories/marcus/project-x/pullrequests/7/decline",
+		"POST /2.0/repositories/marcus/project-x/pullrequests/7/approve",
+		"DELETE /2.0/repositories/marcus/project-x/pullrequests/7/approve",
+		"POST /2.0/repositories/marcus/project-x/pullrequests/7/request-changes",
+		"DELETE /2.0/repositories/marcus/project-x/pullrequests/7/request-changes",
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("requests %v; want %v", requests, want)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("request [%v]; want [%v]", requests[i], want[i])
+		}
+	}
+}
+
+var samplePullRequests = `
+{
+    "pagelen": 10,
+    "page": 1,
+    "size": 1,
+    "values": [
+        {
+            "id": 7,
+            "title": "Add some more things",
+            "description": "",
+            "state": "OPEN",
+            "author": {
+                "type": "user",
+                "uuid": "{a1b2}",
+                "display_name": "Marcus Bertrand",
+                "nickname": "marcus"
+            },
+            "source": {
+                "branch": {"name": "feature"},
+                "commit": {"hash": "620ade18607a"},
+                "repository": {"full_name": "marcus/project-x", "name": "Project X", "uuid": "{e5f6}"}
+            },
+            "destination": {
+                "branch": {"name": "master"},
+                "commit": {"hash": "702c70160afc"},
+                "repository": {"full_name": "marcus/project-x", "name": "Project X", "uuid": "{e5f6}"}
+            },
+            "merge_commit": null,
+            "close_source_branch": false,
+            "participants": [
+                {
+                    "user": {"uuid": "{c3d4}", "display_name": "Jane"},
+                    "role": "REVIEWER",
+                    "approved": true,
+                    "state": "approved",
+                    "participated_on": "2012-05-30T06:00:00.000000+00:00"
+                }
+            ],
+            "comment_count": 2,
+            "task_count": 0,
+            "created_on": "2012-05-30T05:58:56.123456+00:00",
+            "updated_on": "2012-05-30T06:00:00.000000+00:00",
+            "links": {
+                "html": {"href": "https://bitbucket.org/marcus/project-x/pull-requests/7"}
+            }
+        }
+    ]
+}
+`
+
+func Test_PullRequestsDiff(t *testing.T) {
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2.0/repositories/marcus/project-x/pullrequests/7/diff":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(sampleDiff))
+		case "/2.0/repositories/marcus/project-x/pullrequests/7/patch":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("From 620ade18607a\n" + sampleDiff))
+		case "/2.0/repositories/marcus/project-x/pullrequests/7/diffstat":
+			w.Write([]byte(sampleDiffStat))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ctx := context.Background()
+
+	// DIFF as a string
+	diff, err := c.PullRequests.Diff(ctx, "marcus", "project-x", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != sampleDiff {
+		t.Errorf("diff [%v]; want [%v]", diff, sampleDiff)
+	}
+
+	// PATCH as a stream
+	rc, err := c.PullRequests.PatchReader(ctx, "marcus", "project-x", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch, _ := ioutil.ReadAll(rc)
+	rc.Close()
+	if !strings.HasPrefix(string(patch), "From 620ade18607a") {
+		t.Errorf("patch [%s]; expected patch header", patch)
+	}
+
+	// DIFFSTAT
+	stats, err := c.PullRequests.Diffstat(ctx, "marcus", "project-x", 7, nil).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("diffstat count [%v]; want [%v]", len(stats), 2)
+	}
+	if stats[0].Status != DiffStatusModified || stats[0].LinesAdded != 1 || stats[0].New.Path != "somefile.py" {
+		t.Errorf("diffstat [%v] +%v [%v]", stats[0].Status, stats[0].LinesAdded, stats[0].New.Path)
+	}
+	if stats[1].Status != DiffStatusRemoved || stats[1].New != nil || stats[1].Old.Path != "old.py" {
+		t.Errorf("expected old.py to be removed; got %v", stats[1])
+	}
+
+	// errors are returned before streaming
+	if _, err := c.PullRequests.DiffReader(ctx, "marcus", "project-x", 8); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected error [%v]; got [%v]", ErrNotFound, err)
+	}
+}
+
+var sampleDiff = `diff --git a/somefile.py b/somefile.py
+--- a/somefile.py
++++ b/somefile.py
+@@ -1 +1,2 @@
+ print("hello")
++print("world")
+`
+
+var sampleDiffStat = `
+{
+    "pagelen": 500,
+    "page": 1,
+    "size": 2,
+    "values": [
+        {
+            "type": "diffstat",
+            "status": "modified",
+            "lines_added": 1,
+            "lines_removed": 0,
+            "old": {"path": "somefile.py", "escaped_path": "somefile.py", "type": "commit_file"},
+            "new": {"path": "somefile.py", "escaped_path": "somefile.py", "type": "commit_file"}
+        },
+        {
+            "type": "diffstat",
+            "status": "removed",
+            "lines_added": 0,
+            "lines_removed": 12,
+            "old": {"path": "old.py", "escaped_path": "old.py", "type": "commit_file"},
+            "new": null
+        }
+    ]
+}
+`
@@ -0,0 +1,181 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by a fail-fast RateLimiter when a request
+// would exceed the configured rate.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimiter is a token bucket that limits the rate at which a Client
+// sends requests. A single RateLimiter may be shared by many Clients and
+// goroutines, and is safe for concurrent use.
+//
+// The exported fields must be set before the RateLimiter is first used.
+type RateLimiter struct {
+	// When FailFast is true, requests that would exceed the rate fail
+	// immediately with ErrRateLimited rather than waiting for a token.
+	FailFast bool
+
+	// When Adaptive is true, the rate is adjusted from the X-RateLimit-*
+	// and Retry-After headers of each response, so the limiter slows down
+	// as the Bitbucket quota runs out.
+	Adaptive bool
+
+	mu     sync.Mutex
+	limit  float64 // configured tokens per second
+	rate   float64 // current tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// when the quota is used up, the time it resets
+	resetAt time.Time
+}
+
+// NewRateLimiter creates a RateLimiter that allows n requests per
+// interval, with bursts of up to burst requests. For example, Bitbucket's
+// default quota of 1000 requests per hour would be:
+//
+//	NewRateLimiter(1000, time.Hour, 10)
+func NewRateLimiter(n int, per time.Duration, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	rate := float64(n) / per.Seconds()
+	return &RateLimiter{
+		limit:  rate,
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent, or the context is done.
+// If the RateLimiter is FailFast, Wait returns ErrRateLimited instead
+// of blocking.
+//
+// Once an Adaptive RateLimiter sees the quota is used up, Wait blocks
+// (or fails fast) until the time the quota resets.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	// if the quota is used up, no requests may be sent until it resets
+	for {
+		l.mu.Lock()
+		if l.resetAt.IsZero() || !time.Now().Before(l.resetAt) {
+			break
+		}
+		if l.FailFast {
+			l.mu.Unlock()
+			return ErrRateLimited
+		}
+		wait := time.Until(l.resetAt)
+		l.mu.Unlock()
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+
+	// the quota has reset, so we can go back to the configured rate
+	now := time.Now()
+	if !l.resetAt.IsZero() {
+		l.resetAt = time.Time{}
+		l.rate = l.limit
+		l.tokens = l.burst
+		l.last = now
+	}
+	l.refill(now)
+
+	if l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+
+	if l.FailFast || l.rate <= 0 {
+		l.mu.Unlock()
+		return ErrRateLimited
+	}
+
+	// reserve a token, and wait until it has been refilled
+	wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	l.tokens--
+	l.mu.Unlock()
+
+	if err := sleep(ctx, wait); err != nil {
+		// give back the token we reserved
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// refill adds the tokens accrued since the last refill. The caller
+// must hold l.mu.
+func (l *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	if elapsed > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+elapsed*l.rate)
+		l.last = now
+	}
+}
+
+// observe adjusts the rate from the rate limit headers of a response.
+func (l *RateLimiter) observe(resp *http.Response) {
+	if !l.Adaptive || resp == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.refill(now)
+	h := resp.Header
+
+	// if we've been told to back off, no tokens are available
+	// until the Retry-After period has passed
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait, ok := retryAfter(h)
+		if !ok {
+			wait, ok = rateLimitReset(h)
+		}
+		if ok && l.rate > 0 {
+			l.tokens = math.Min(l.tokens, -wait.Seconds()*l.rate)
+		}
+		return
+	}
+
+	// spread the requests remaining in the window evenly
+	// over the time left until the window resets
+	remaining, rerr := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	reset, ok := rateLimitReset(h)
+	if rerr == nil && ok && reset > 0 {
+		l.rate = math.Min(l.limit, float64(remaining)/reset.Seconds())
+		l.resetAt = time.Time{}
+		if remaining <= 0 {
+			l.rate = 0
+			l.resetAt = now.Add(reset)
+		}
+		return
+	}
+
+	// Bitbucket Cloud only tells us when we're close to the limit,
+	// so we'll stop bursting until the quota recovers
+	if strings.EqualFold(h.Get("X-RateLimit-NearLimit"), "true") {
+		l.tokens = math.Min(l.tokens, 0)
+	}
+	l.rate = l.limit
+}
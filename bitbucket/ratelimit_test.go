@@ -0,0 +1,141 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_RateLimiter(t *testing.T) {
+	l := NewRateLimiter(100, time.Second, 2)
+	ctx := context.Background()
+
+	// the burst is available immediately
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Millisecond {
+		t.Errorf("burst took [%v]; expected no wait", elapsed)
+	}
+
+	// after which we wait ~10ms per request
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("3 requests took [%v]; want at least [%v]", elapsed, 20*time.Millisecond)
+	}
+
+	// a cancelled wait returns the context's error
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	slow := NewRateLimiter(1, time.Hour, 1)
+	if err := slow.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := slow.Wait(cancelled); err != context.Canceled {
+		t.Errorf("expected error [%v]; got [%v]", context.Canceled, err)
+	}
+}
+
+func Test_RateLimiterFailFast(t *testing.T) {
+	l := NewRateLimiter(1, time.Hour, 1)
+	l.FailFast = true
+
+	c := New(&Anonymous{}, WithBaseURL("http://127.0.0.1:0"), WithRateLimiter(l))
+	l.Wait(context.Background())
+
+	if _, err := c.Users.Current(context.Background()); err != ErrRateLimited {
+		t.Errorf("expected error [%v]; got [%v]", ErrRateLimited, err)
+	}
+}
+
+func Test_RateLimiterAdaptive(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(100*time.Second).Unix(), 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "10")
+		w.Header().Set("X-RateLimit-Reset", reset)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	l := NewRateLimiter(1000, time.Second, 10)
+	l.Adaptive = true
+	c := New(&Anonymous{}, WithBaseURL(srv.URL), WithHTTPClient(srv.Client()), WithRateLimiter(l))
+
+	// concurrent requests share the limiter
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Users.Current(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 10 requests remaining over ~100 seconds
+	l.mu.Lock()
+	rate := l.rate
+	l.mu.Unlock()
+	if rate < 0.09 || rate > 0.11 {
+		t.Errorf("adapted rate [%v]; want ~[%v]", rate, 0.1)
+	}
+}
+
+func Test_RateLimiterQuotaExhausted(t *testing.T) {
+	l := NewRateLimiter(1000, time.Second, 10)
+	l.Adaptive = true
+
+	// the quota is used up until the start of the next second
+	reset := time.Now().Truncate(time.Second).Add(time.Second)
+	l.observe(&http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"X-Ratelimit-Remaining": {"0"},
+		"X-Ratelimit-Reset":     {strconv.FormatInt(reset.Unix(), 10)},
+	}})
+
+	// a fail-fast limiter fails until the reset
+	l.FailFast = true
+	if err := l.Wait(context.Background()); err != ErrRateLimited {
+		t.Errorf("fail fast error [%v]; want [%v]", err, ErrRateLimited)
+	}
+	l.FailFast = false
+
+	// a blocking limiter gives up if the context is done first
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); err != context.Canceled {
+		t.Errorf("cancelled error [%v]; want [%v]", err, context.Canceled)
+	}
+
+	// otherwise it blocks until the reset, then restores the rate
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if now := time.Now(); now.Before(reset) {
+		t.Errorf("Wait returned at [%v]; want after the reset at [%v]", now, reset)
+	}
+	for i := 0; i < 5; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.mu.Lock()
+	rate := l.rate
+	l.mu.Unlock()
+	if rate != 1000 {
+		t.Errorf("rate after the reset [%v]; want [%v]", rate, 1000)
+	}
+}
@@ -0,0 +1,363 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBodyBytes is the largest delivery body a Receiver accepts
+// by default, and RequireSignature accepts. Bitbucket's payloads are far smaller, since a push
+// includes at most five commits.
+const DefaultMaxBodyBytes = 10 << 20
+
+// EventHandler handles a webhook event delivered to a Receiver.
+type EventHandler func(ctx context.Context, event Event) error
+
+// Receiver is an http.Handler that receives webhook deliveries. Each
+// delivery is verified, decoded with ParseWebhook, and passed to the
+// handlers registered for its event key:
+//
+//	receiver := &bitbucket.Receiver{Secrets: []string{secret}}
+//	receiver.OnPush(func(ctx context.Context, e *bitbucket.PushEvent) error {
+//		...
+//	})
+//	http.Handle("/bitbucket", receiver)
+//
+// The Receiver responds as soon as a delivery is queued, and runs the
+// handlers on a bounded pool of workers, so that Bitbucket doesn't time
+// out waiting for slow handlers. A panicking handler is recovered, and
+// logged along with handler errors.
+//
+// A Receiver without Secrets rejects every delivery with 500 Internal
+// Server Error, unless InsecureSkipVerify is set.
+//
+// The fields must be set, and the handlers registered, before the
+// Receiver receives its first delivery.
+type Receiver struct {
+	// The secrets the webhooks are signed with. Deliveries are rejected
+	// unless signed with one of them; see VerifySignature. Empty secrets
+	// are ignored.
+	Secrets []string
+
+	// If set, signatures are not checked, and deliveries are handled
+	// whether signed or not. Only for webhooks without a secret, behind
+	// some other means of authenticating Bitbucket, ie an Allowlist.
+	InsecureSkipVerify bool
+
+	// If set, deliveries are rejected unless sent from one of the
+	// allowed ranges.
+	Allowlist *Allowlist
+
+	// The number of workers running handlers, and the number of
+	// deliveries queued for them, 4 and 64 if zero. Deliveries are
+	// rejected with 503 Service Unavailable while the queue is full,
+	// and Bitbucket will retry them later.
+	Workers   int
+	QueueSize int
+
+	// The largest delivery body accepted, in bytes. Larger deliveries
+	// are rejected with 413 Request Entity Too Large before they are
+	// verified. DefaultMaxBodyBytes if zero.
+	MaxBodyBytes int64
+
+	// How long a delivery's X-Request-UUID is remembered, so that
+	// retried deliveries aren't handled twice. One hour if zero.
+	DedupeWindow time.Duration
+
+	// Receives errors returned by handlers, and recovered panics.
+	Logger Logger
+
+	mu       sync.RWMutex
+	handlers map[string][]EventHandler
+	queue    chan Event
+	closed   bool
+	start    sync.Once
+	workers  sync.WaitGroup
+
+	seenMu    sync.Mutex
+	seen      map[string]time.Time
+	nextPrune time.Time
+}
+
+// On registers a handler for the event key, one of the Event constants.
+// Several handlers may be registered for the same key.
+func (r *Receiver) On(key string, h EventHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.handlers == nil {
+		r.handlers = map[string][]EventHandler{}
+	}
+	r.handlers[key] = append(r.handlers[key], h)
+}
+
+// on registers a handler taking the concrete event type delivered
+// for the key.
+func on[T Event](r *Receiver, key string, fn func(context.Context, T) error) {
+	r.On(key, func(ctx context.Context, event Event) error {
+		return fn(ctx, event.(T))
+	})
+}
+
+// OnPush registers a handler for repo:push.
+func (r *Receiver) OnPush(fn func(context.Context, *PushEvent) error) {
+	on(r, EventRepoPush, fn)
+}
+
+// OnFork registers a handler for repo:fork.
+func (r *Receiver) OnFork(fn func(context.Context, *ForkEvent) error) {
+	on(r, EventRepoFork, fn)
+}
+
+// OnRepoUpdated registers a handler for repo:updated.
+func (r *Receiver) OnRepoUpdated(fn func(context.Context, *RepoUpdatedEvent) error) {
+	on(r, EventRepoUpdated, fn)
+}
+
+// OnCommitStatusCreated registers a handler for repo:commit_status_created.
+func (r *Receiver) OnCommitStatusCreated(fn func(context.Context, *CommitStatusEvent) error) {
+	on(r, EventRepoCommitStatusCreated, fn)
+}
+
+// OnCommitStatusUpdated registers a handler for repo:commit_status_updated.
+func (r *Receiver) OnCommitStatusUpdated(fn func(context.Context, *CommitStatusEvent) error) {
+	on(r, EventRepoCommitStatusUpdated, fn)
+}
+
+// OnPullRequestCreated registers a handler for pullrequest:created.
+func (r *Receiver) OnPullRequestCreated(fn func(context.Context, *PullRequestEvent) error) {
+	on(r, EventPullRequestCreated, fn)
+}
+
+// OnPullRequestUpdated registers a handler for pullrequest:updated.
+func (r *Receiver) OnPullRequestUpdated(fn func(context.Context, *PullRequestEvent) error) {
+	on(r, EventPullRequestUpdated, fn)
+}
+
+// OnPullRequestApproved registers a handler for pullrequest:approved.
+func (r *Receiver) OnPullRequestApproved(fn func(context.Context, *PullRequestEvent) error) {
+	on(r, EventPullRequestApproved, fn)
+}
+
+// OnPullRequestUnapproved registers a handler for pullrequest:unapproved.
+func (r *Receiver) OnPullRequestUnapproved(fn func(context.Context, *PullRequestEvent) error) {
+	on(r, EventPullRequestUnapproved, fn)
+}
+
+// OnPullRequestFulfilled registers a handler for pullrequest:fulfilled,
+// when a pull request is merged.
+func (r *Receiver) OnPullRequestFulfilled(fn func(context.Context, *PullRequestEvent) error) {
+	on(r, EventPullRequestFulfilled, fn)
+}
+
+// OnPullRequestRejected registers a handler for pullrequest:rejected,
+// when a pull request is declined.
+func (r *Receiver) OnPullRequestRejected(fn func(context.Context, *PullRequestEvent) error) {
+	on(r, EventPullRequestRejected, fn)
+}
+
+// OnPullRequestCommentCreated registers a handler for
+// pullrequest:comment_created.
+func (r *Receiver) OnPullRequestCommentCreated(fn func(context.Context, *PullRequestCommentEvent) error) {
+	on(r, EventPullRequestCommentCreated, fn)
+}
+
+// ServeHTTP receives a webhook delivery.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Allowlist != nil && !r.Allowlist.AllowedRequest(req) {
+		http.Error(w, "sender not allowed", http.StatusForbidden)
+		return
+	}
+
+	// refuse to handle deliveries anyone could have sent
+	if !r.InsecureSkipVerify && !hasSecret(r.Secrets) {
+		r.logger().ErrorContext(req.Context(), "bitbucket: webhook receiver has no secrets configured")
+		http.Error(w, "receiver not configured", http.StatusInternalServerError)
+		return
+	}
+
+	limit := r.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	req.Body = http.MaxBytesReader(w, req.Body, limit)
+
+	var event Event
+	var err error
+	var tooLarge *http.MaxBytesError
+	if !r.InsecureSkipVerify {
+		_, err = VerifyWebhook(req, r.Secrets...)
+	}
+	if err == nil {
+		event, err = ParseWebhook(req)
+	}
+	switch {
+	case errors.As(err, &tooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignature):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	info := event.Info()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// nothing to do, but Bitbucket shouldn't retry
+	if len(r.handlers[info.Key]) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// a retry of a delivery we've already queued
+	if !r.claim(info.RequestUUID) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.closed {
+		r.unclaim(info.RequestUUID)
+		http.Error(w, "receiver closed", http.StatusServiceUnavailable)
+		return
+	}
+	r.start.Do(r.startWorkers)
+	select {
+	case r.queue <- event:
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		r.unclaim(info.RequestUUID)
+		http.Error(w, "too many deliveries", http.StatusServiceUnavailable)
+	}
+}
+
+// Close stops the Receiver accepting deliveries, and waits for the
+// handlers of those already queued to finish.
+func (r *Receiver) Close() error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		if r.queue != nil {
+			close(r.queue)
+		}
+	}
+	r.mu.Unlock()
+
+	r.workers.Wait()
+	return nil
+}
+
+func (r *Receiver) startWorkers() {
+	workers, size := r.Workers, r.QueueSize
+	if workers <= 0 {
+		workers = 4
+	}
+	if size <= 0 {
+		size = 64
+	}
+
+	r.queue = make(chan Event, size)
+	r.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func(queue <-chan Event) {
+			defer r.workers.Done()
+			for event := range queue {
+				r.handle(event)
+			}
+		}(r.queue)
+	}
+}
+
+// handle runs the handlers registered for the event.
+func (r *Receiver) handle(event Event) {
+	info := event.Info()
+	r.mu.RLock()
+	handlers := r.handlers[info.Key]
+	r.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := r.run(h, event); err != nil {
+			r.logger().ErrorContext(context.Background(), "bitbucket: webhook handler failed",
+				"event", info.Key,
+				"request", info.RequestUUID,
+				"error", err)
+		}
+	}
+}
+
+// run runs a handler, converting a panic into an error.
+func (r *Receiver) run(h EventHandler, event Event) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v\n%s", v, debug.Stack())
+		}
+	}()
+	return h(context.Background(), event)
+}
+
+// claim records the delivery as received, returning false if it has
+// already been received within the DedupeWindow. Deliveries without a
+// request UUID are never considered duplicates.
+func (r *Receiver) claim(id string) bool {
+	if len(id) == 0 {
+		return true
+	}
+
+	window := r.DedupeWindow
+	if window <= 0 {
+		window = time.Hour
+	}
+
+	r.seenMu.Lock()
+	defer r.seenMu.Unlock()
+	now := time.Now()
+	if r.seen == nil {
+		r.seen = map[string]time.Time{}
+	}
+
+	// forget deliveries outside the window, at most once a minute
+	if now.After(r.nextPrune) {
+		for k, t := range r.seen {
+			if now.Sub(t) > window {
+				delete(r.seen, k)
+			}
+		}
+		r.nextPrune = now.Add(time.Minute)
+	}
+
+	if t, ok := r.seen[id]; ok && now.Sub(t) <= window {
+		return false
+	}
+	r.seen[id] = now
+	return true
+}
+
+// unclaim forgets a delivery that could not be queued, so that it is
+// handled when Bitbucket retries it.
+func (r *Receiver) unclaim(id string) {
+	if len(id) == 0 {
+		return
+	}
+	r.seenMu.Lock()
+	delete(r.seen, id)
+	r.seenMu.Unlock()
+}
+
+func (r *Receiver) logger() Logger {
+	if r.Logger == nil {
+		return nopLogger{}
+	}
+	return r.Logger
+}
@@ -0,0 +1,167 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func deliver(h http.Handler, key, id, payload, signature string) int {
+	r := httptest.NewRequest("POST", "/hook", strings.NewReader(payload))
+	r.Header.Set("X-Event-Key", key)
+	r.Header.Set("X-Request-UUID", id)
+	if len(signature) != 0 {
+		r.Header.Set(SignatureHeader, signature)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code
+}
+
+func Test_Receiver(t *testing.T) {
+	pushes := make(chan *PushEvent, 10)
+	receiver := &Receiver{Secrets: []string{"new", "old"}}
+	receiver.OnPush(func(ctx context.Context, e *PushEvent) error {
+		pushes <- e
+		return nil
+	})
+	receiver.OnPullRequestCreated(func(ctx context.Context, e *PullRequestEvent) error {
+		panic("handler bug")
+	})
+
+	tests := []struct {
+		key, id, payload, signature string
+		want                        int
+	}{
+		{EventRepoPush, "{r1}", samplePushEvent, sign(samplePushEvent, "old"), http.StatusAccepted},
+		// a retry of the same delivery
+		{EventRepoPush, "{r1}", samplePushEvent, sign(samplePushEvent, "old"), http.StatusOK},
+		{EventRepoPush, "{r2}", samplePushEvent, sign(samplePushEvent, "other"), http.StatusUnauthorized},
+		{EventRepoPush, "{r3}", samplePushEvent, "", http.StatusUnauthorized},
+		{"repo:imported", "{r4}", `{}`, sign(`{}`, "new"), http.StatusBadRequest},
+		// no handlers registered
+		{EventRepoFork, "{r5}", `{}`, sign(`{}`, "new"), http.StatusNoContent},
+		// the handler panics, which shouldn't stop the worker
+		{EventPullRequestCreated, "{r6}", samplePullRequestEvent, sign(samplePullRequestEvent, "new"), http.StatusAccepted},
+		{EventRepoPush, "{r7}", samplePushEvent, sign(samplePushEvent, "new"), http.StatusAccepted},
+	}
+	for _, test := range tests {
+		if code := deliver(receiver, test.key, test.id, test.payload, test.signature); code != test.want {
+			t.Errorf("delivery [%v %v] status [%v]; want [%v]", test.key, test.id, code, test.want)
+		}
+	}
+
+	receiver.Close()
+	close(pushes)
+
+	var ids []string
+	for e := range pushes {
+		if len(e.Push.Changes) != 2 {
+			t.Errorf("push changes %v", e.Push.Changes)
+		}
+		ids = append(ids, e.RequestUUID)
+	}
+	if len(ids) != 2 {
+		t.Errorf("handled pushes %v; want [{r1} {r7}]", ids)
+	}
+
+	// a closed receiver should ask Bitbucket to retry later
+	if code := deliver(receiver, EventRepoPush, "{r8}", samplePushEvent, sign(samplePushEvent, "new")); code != http.StatusServiceUnavailable {
+		t.Errorf("status [%v]; want [%v]", code, http.StatusServiceUnavailable)
+	}
+}
+
+func Test_ReceiverQueueFull(t *testing.T) {
+	block := make(chan struct{})
+	errs := make(chan error, 10)
+	receiver := &Receiver{InsecureSkipVerify: true, Workers: 1, QueueSize: 1, Logger: &errorLogger{errs}}
+	receiver.OnPush(func(ctx context.Context, e *PushEvent) error {
+		<-block
+		return errors.New("failed")
+	})
+
+	// the first is taken by the worker, and the second queued, but the
+	// worker may not have taken the first by the time the second
+	// arrives, so we'll send three to be sure one is rejected
+	codes := map[int]int{}
+	for i := 0; i < 3; i++ {
+		codes[deliver(receiver, EventRepoPush, "{r"+string(rune('0'+i))+"}", samplePushEvent, "")]++
+	}
+	if codes[http.StatusServiceUnavailable] == 0 {
+		t.Errorf("statuses %v; want a delivery rejected", codes)
+	}
+
+	// every accepted delivery should be handled before Close returns
+	close(block)
+	receiver.Close()
+	if len(errs) != codes[http.StatusAccepted] {
+		t.Errorf("logged %v errors; want %v", len(errs), codes[http.StatusAccepted])
+	}
+}
+
+type errorLogger struct {
+	errs chan error
+}
+
+func (l *errorLogger) DebugContext(ctx context.Context, msg string, args ...any) {}
+func (l *errorLogger) InfoContext(ctx context.Context, msg string, args ...any)  {}
+func (l *errorLogger) WarnContext(ctx context.Context, msg string, args ...any)  {}
+func (l *errorLogger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	for i := 0; i+1 < len(args); i += 2 {
+		if err, ok := args[i+1].(error); ok {
+			l.errs <- err
+		}
+	}
+}
+
+func Test_ReceiverMaxBodyBytes(t *testing.T) {
+	signed := &Receiver{Secrets: []string{"new"}, MaxBodyBytes: 64}
+	unsigned := &Receiver{InsecureSkipVerify: true, MaxBodyBytes: 64}
+	for _, receiver := range []*Receiver{signed, unsigned} {
+		receiver.OnPush(func(ctx context.Context, e *PushEvent) error {
+			t.Errorf("expected the delivery to be rejected")
+			return nil
+		})
+
+		// rejected before the signature is checked
+		if code := deliver(receiver, EventRepoPush, "{r1}", samplePushEvent, sign(samplePushEvent, "new")); code != http.StatusRequestEntityTooLarge {
+			t.Errorf("status [%v]; want [%v]", code, http.StatusRequestEntityTooLarge)
+		}
+		receiver.Close()
+	}
+}
+
+func Test_ReceiverNoSecrets(t *testing.T) {
+	for _, secrets := range [][]string{nil, {""}} {
+		logs := &countLogger{}
+		receiver := &Receiver{Secrets: secrets, Logger: logs}
+		receiver.OnPush(func(ctx context.Context, e *PushEvent) error {
+			t.Errorf("expected the delivery to be rejected")
+			return nil
+		})
+
+		// even a delivery signed with the empty secret is refused
+		if code := deliver(receiver, EventRepoPush, "{r1}", samplePushEvent, sign(samplePushEvent, "")); code != http.StatusInternalServerError {
+			t.Errorf("secrets %q status [%v]; want [%v]", secrets, code, http.StatusInternalServerError)
+		}
+		if code := deliver(receiver, EventRepoPush, "{r2}", samplePushEvent, ""); code != http.StatusInternalServerError {
+			t.Errorf("secrets %q status [%v]; want [%v]", secrets, code, http.StatusInternalServerError)
+		}
+		if logs.errors != 2 {
+			t.Errorf("secrets %q logged %v errors; want 2", secrets, logs.errors)
+		}
+		receiver.Close()
+	}
+}
+
+type countLogger struct {
+	errors int
+}
+
+func (l *countLogger) DebugContext(ctx context.Context, msg string, args ...any) {}
+func (l *countLogger) InfoContext(ctx context.Context, msg string, args ...any)  {}
+func (l *countLogger) WarnContext(ctx context.Context, msg string, args ...any)  {}
+func (l *countLogger) ErrorContext(ctx context.Context, msg string, args ...any) { l.errors++ }
@@ -0,0 +1,191 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// The types of a ref.
+const (
+	RefTypeBranch = "branch"
+	RefTypeTag    = "tag"
+)
+
+// Ref is a branch or tag in a repository.
+type Ref struct {
+	// One of the RefType constants.
+	Type string `json:"type"`
+	Name string `json:"name"`
+
+	// The commit the ref points to.
+	Target *RepoCommit `json:"target"`
+
+	// The message, author and date of an annotated tag. These are
+	// empty for branches and lightweight tags.
+	Message string        `json:"message,omitempty"`
+	Tagger  *CommitAuthor `json:"tagger,omitempty"`
+	Date    time.Time     `json:"date,omitempty"`
+
+	// The merge strategies allowed when merging pull requests into a
+	// branch, and the default one. These are empty for tags.
+	MergeStrategies      []string `json:"merge_strategies,omitempty"`
+	DefaultMergeStrategy string   `json:"default_merge_strategy,omitempty"`
+
+	Links *Links `json:"links,omitempty"`
+}
+
+// Hash is the hash of the commit the ref points to.
+func (r *Ref) Hash() string {
+	if r.Target == nil {
+		return ""
+	}
+	return r.Target.Hash
+}
+
+// The sort orders of a listing of refs.
+const (
+	RefSortName     = "name"
+	RefSortNameDesc = "-name"
+	RefSortDate     = "target.date"
+	RefSortDateDesc = "-target.date"
+)
+
+// RefListOptions filters, sorts and paginates a listing of refs.
+type RefListOptions struct {
+	ListOptions
+
+	// One of the RefSort constants. Refs are sorted by name, using
+	// natural ordering (ie "v2" before "v10"), if empty.
+	Sort string
+
+	// Only list refs whose name contains this string.
+	Name string
+
+	// A raw query, ie `target.date > 2024-01-01`, combined with Name
+	// if both are set.
+	//
+	// https://developer.atlassian.com/cloud/bitbucket/rest/intro/#filtering
+	Query string
+}
+
+// params converts the options to query parameters.
+func (o *RefListOptions) params() (url.Values, *ListOptions) {
+	params := url.Values{}
+	if o == nil {
+		return params, nil
+	}
+	if len(o.Sort) != 0 {
+		params.Set("sort", o.Sort)
+	}
+	q := o.Query
+	if len(o.Name) != 0 {
+		name := fmt.Sprintf("name ~ %q", o.Name)
+		if len(q) != 0 {
+			q = fmt.Sprintf("(%s) AND %s", q, name)
+		} else {
+			q = name
+		}
+	}
+	if len(q) != 0 {
+		params.Set("q", q)
+	}
+	return params, &o.ListOptions
+}
+
+// Use the refs resource to list, create and delete the branches and
+// tags of a repository. This resource uses the 2.0 API.
+//
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-refs/
+type RefResource struct {
+	client *Client
+}
+
+// Gets the branches and tags of a repository.
+func (r *RefResource) List(ctx context.Context, owner, slug string, opts *RefListOptions) *Iterator[*Ref] {
+	params, listOpts := opts.params()
+	path := fmt.Sprintf("/repositories/%s/%s/refs", owner, slug)
+	return newIterator[*Ref](ctx, r.client, path, params, listOpts)
+}
+
+// Gets the branches of a repository.
+func (r *RefResource) ListBranches(ctx context.Context, owner, slug string, opts *RefListOptions) *Iterator[*Ref] {
+	params, listOpts := opts.params()
+	path := fmt.Sprintf("/repositories/%s/%s/refs/branches", owner, slug)
+	return newIterator[*Ref](ctx, r.client, path, params, listOpts)
+}
+
+// Gets the tags of a repository.
+func (r *RefResource) ListTags(ctx context.Context, owner, slug string, opts *RefListOptions) *Iterator[*Ref] {
+	params, listOpts := opts.params()
+	path := fmt.Sprintf("/repositories/%s/%s/refs/tags", owner, slug)
+	return newIterator[*Ref](ctx, r.client, path, params, listOpts)
+}
+
+// Gets the named branch.
+func (r *RefResource) FindBranch(ctx context.Context, owner, slug, name string) (*Ref, error) {
+	return r.find(ctx, fmt.Sprintf("/repositories/%s/%s/refs/branches/%s", owner, slug, name))
+}
+
+// Gets the named tag.
+func (r *RefResource) FindTag(ctx context.Context, owner, slug, name string) (*Ref, error) {
+	return r.find(ctx, fmt.Sprintf("/repositories/%s/%s/refs/tags/%s", owner, slug, name))
+}
+
+func (r *RefResource) find(ctx context.Context, path string) (*Ref, error) {
+	ref := Ref{}
+
+	if err := r.client.do2(ctx, "GET", path, nil, nil, &ref); err != nil {
+		return nil, err
+	}
+
+	return &ref, nil
+}
+
+// Creates a branch pointing at the given revision, which may be a
+// commit hash or the name of another branch or tag.
+func (r *RefResource) CreateBranch(ctx context.Context, owner, slug, name, revision string) (*Ref, error) {
+	body := map[string]interface{}{
+		"name":   name,
+		"target": map[string]string{"hash": revision},
+	}
+	path := fmt.Sprintf("/repositories/%s/%s/refs/branches", owner, slug)
+	return r.create(ctx, path, body)
+}
+
+// Creates a tag pointing at the given revision. The tag is annotated
+// with the message, unless the message is empty.
+func (r *RefResource) CreateTag(ctx context.Context, owner, slug, name, revision, message string) (*Ref, error) {
+	body := map[string]interface{}{
+		"name":   name,
+		"target": map[string]string{"hash": revision},
+	}
+	if len(message) != 0 {
+		body["message"] = message
+	}
+	path := fmt.Sprintf("/repositories/%s/%s/refs/tags", owner, slug)
+	return r.create(ctx, path, body)
+}
+
+func (r *RefResource) create(ctx context.Context, path string, body map[string]interface{}) (*Ref, error) {
+	ref := Ref{}
+
+	if err := r.client.do2(ctx, "POST", path, nil, body, &ref); err != nil {
+		return nil, err
+	}
+
+	return &ref, nil
+}
+
+// Deletes the named branch.
+func (r *RefResource) DeleteBranch(ctx context.Context, owner, slug, name string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/refs/branches/%s", owner, slug, name)
+	return r.client.do2(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// Deletes the named tag.
+func (r *RefResource) DeleteTag(ctx context.Context, owner, slug, name string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/refs/tags/%s", owner, slug, name)
+	return r.client.do2(ctx, "DELETE", path, nil, nil, nil)
+}
@@ -0,0 +1,111 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_Refs(t *testing.T) {
+	var requests []string
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /2.0/repositories/marcus/project-x/refs/tags":
+			q := r.URL.Query()
+			if q.Get("sort") != RefSortDateDesc || q.Get("q") != `(target.date > 2012-01-01) AND name ~ "v1."` {
+				t.Errorf("list query [%v]", r.URL.RawQuery)
+			}
+			w.Write([]byte(sampleRefs))
+		case "POST /2.0/repositories/marcus/project-x/refs/branches":
+			body := decodeBody(t, r)
+			target, ok := body["target"].(map[string]interface{})
+			if !ok || body["name"] != "feature" || target["hash"] != "620ade18607a" {
+				t.Errorf("branch body [%v]", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"type": "branch", "name": "feature", "target": {"hash": "620ade18607ac42d872b568bb92acaa9a28620e9"}}`))
+		case "POST /2.0/repositories/marcus/project-x/refs/tags":
+			body := decodeBody(t, r)
+			if body["name"] != "v1.1" || body["message"] != "Release 1.1" {
+				t.Errorf("tag body [%v]", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"type": "tag", "name": "v1.1", "message": "Release 1.1"}`))
+		case "DELETE /2.0/repositories/marcus/project-x/refs/branches/feature/x":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request [%v %v]", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+
+	// LIST the tags
+	tags, err := c.Refs.ListTags(ctx, "marcus", "project-x", &RefListOptions{
+		Sort:  RefSortDateDesc,
+		Name:  "v1.",
+		Query: "target.date > 2012-01-01",
+	}).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2012, 5, 30, 3, 58, 56, 0, time.UTC)
+	if len(tags) != 2 || tags[0].Hash() != "620ade18607ac42d872b568bb92acaa9a28620e9" || !tags[0].Target.Date.Equal(date) {
+		t.Errorf("tags %v; want v1.1 at 620ade18607a on %v", tags, date)
+	}
+	if tags[1].Type != RefTypeTag || tags[1].Message != "" {
+		t.Errorf("expected v1.0 to be a lightweight tag; got [%v] [%v]", tags[1].Type, tags[1].Message)
+	}
+
+	// CREATE a branch and a tag
+	branch, err := c.Refs.CreateBranch(ctx, "marcus", "project-x", "feature", "620ade18607a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if branch.Hash() != "620ade18607ac42d872b568bb92acaa9a28620e9" {
+		t.Errorf("branch hash [%v]", branch.Hash())
+	}
+	if _, err := c.Refs.CreateTag(ctx, "marcus", "project-x", "v1.1", "620ade18607a", "Release 1.1"); err != nil {
+		t.Error(err)
+	}
+
+	// DELETE a branch
+	if err := c.Refs.DeleteBranch(ctx, "marcus", "project-x", "feature/x"); err != nil {
+		t.Error(err)
+	}
+
+	if len(requests) != 4 {
+		t.Errorf("requests %v", requests)
+	}
+}
+
+var sampleRefs = `
+{
+    "pagelen": 10,
+    "values": [
+        {
+            "type": "tag",
+            "name": "v1.1",
+            "message": "Release 1.1\n",
+            "date": "2012-05-30T04:00:00+00:00",
+            "tagger": {"raw": "Marcus Bertrand <marcus@somedomain.com>"},
+            "target": {
+                "type": "commit",
+                "hash": "620ade18607ac42d872b568bb92acaa9a28620e9",
+                "date": "2012-05-30T03:58:56+00:00",
+                "message": "Added some more things to somefile.py\n"
+            }
+        },
+        {
+            "type": "tag",
+            "name": "v1.0",
+            "target": {
+                "type": "commit",
+                "hash": "702c70160afc",
+                "date": "2012-05-29T12:00:00+00:00"
+            }
+        }
+    ]
+}
+`
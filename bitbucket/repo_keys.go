@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 )
@@ -15,11 +16,11 @@ type RepoKeyResource struct {
 }
 
 // Gets a list of the keys associated with a repository.
-func (r *RepoKeyResource) List(owner, slug string) ([]*Key, error) {
+func (r *RepoKeyResource) List(ctx context.Context, owner, slug string) ([]*Key, error) {
 	keys := []*Key{}
 	path := fmt.Sprintf("/repositories/%s/%s/deploy-keys", owner, slug)
 
-	if err := r.client.do("GET", path, nil, nil, &keys); err != nil {
+	if err := r.client.do(ctx, "GET", path, nil, nil, &keys); err != nil {
 		return nil, err
 	}
 
@@ -28,10 +29,10 @@ func (r *RepoKeyResource) List(owner, slug string) ([]*Key, error) {
 
 // Gets the content of the specified key_id.
 // This call requires authentication.
-func (r *RepoKeyResource) Find(owner, slug string, id int) (*Key, error) {
+func (r *RepoKeyResource) Find(ctx context.Context, owner, slug string, id int) (*Key, error) {
 	key := Key{}
 	path := fmt.Sprintf("/repositories/%s/%s/deploy-keys/%v", owner, slug, id)
-	if err := r.client.do("GET", path, nil, nil, &key); err != nil {
+	if err := r.client.do(ctx, "GET", path, nil, nil, &key); err != nil {
 		return nil, err
 	}
 
@@ -40,8 +41,8 @@ func (r *RepoKeyResource) Find(owner, slug string, id int) (*Key, error) {
 
 // Gets the content of the specified key with the
 // given label.
-func (r *RepoKeyResource) FindName(owner, slug, label string) (*Key, error) {
-	keys, err := r.List(owner, slug)
+func (r *RepoKeyResource) FindName(ctx context.Context, owner, slug, label string) (*Key, error) {
+	keys, err := r.List(ctx, owner, slug)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +58,7 @@ func (r *RepoKeyResource) FindName(owner, slug, label string) (*Key, error) {
 
 // Creates a key on the specified repo. You must supply a valid key
 // that is unique across the Bitbucket service.
-func (r *RepoKeyResource) Create(owner, slug, key, label string) (*Key, error) {
+func (r *RepoKeyResource) Create(ctx context.Context, owner, slug, key, label string) (*Key, error) {
 
 	values := url.Values{}
 	values.Add("key", key)
@@ -65,7 +66,7 @@ func (r *RepoKeyResource) Create(owner, slug, key, label string) (*Key, error) {
 
 	k := Key{}
 	path := fmt.Sprintf("/repositories/%s/%s/deploy-keys", owner, slug)
-	if err := r.client.do("POST", path, nil, values, &k); err != nil {
+	if err := r.client.do(ctx, "POST", path, nil, values, &k); err != nil {
 		return nil, err
 	}
 
@@ -74,17 +75,17 @@ func (r *RepoKeyResource) Create(owner, slug, key, label string) (*Key, error) {
 
 // Creates a key on the specified account. You must supply a valid key
 // that is unique across the Bitbucket service.
-func (r *RepoKeyResource) Update(owner, slug, key, label string, id int) (*Key, error) {
+func (r *RepoKeyResource) Update(ctx context.Context, owner, slug, key, label string, id int) (*Key, error) {
 	// There is actually no API to update an existing key
-	r.Delete(owner, slug, id)
-	return r.Create(owner, slug, key, label)
+	r.Delete(ctx, owner, slug, id)
+	return r.Create(ctx, owner, slug, key, label)
 }
 
-func (r *RepoKeyResource) CreateUpdate(owner, slug, key, label string) (*Key, error) {
-	if found, err := r.FindName(owner, slug, label); err == nil {
+func (r *RepoKeyResource) CreateUpdate(ctx context.Context, owner, slug, key, label string) (*Key, error) {
+	if found, err := r.FindName(ctx, owner, slug, label); err == nil {
 		// if the public keys are different we should update
 		if found.Key != key {
-			return r.Update(owner, slug, key, label, found.Id)
+			return r.Update(ctx, owner, slug, key, label, found.Id)
 		}
 
 		// otherwise we should just return the key, since there
@@ -92,23 +93,23 @@ func (r *RepoKeyResource) CreateUpdate(owner, slug, key, label string) (*Key, er
 		return found, nil
 	}
 
-	return r.Create(owner, slug, key, label)
+	return r.Create(ctx, owner, slug, key, label)
 }
 
 // Deletes the key specified by the key_id value.
 // This call requires authentication
-func (r *RepoKeyResource) Delete(owner, slug string, id int) error {
+func (r *RepoKeyResource) Delete(ctx context.Context, owner, slug string, id int) error {
 	path := fmt.Sprintf("/repositories/%s/%s/deploy-keys/%v", owner, slug, id)
-	return r.client.do("DELETE", path, nil, nil, nil)
+	return r.client.do(ctx, "DELETE", path, nil, nil, nil)
 }
 
 // Deletes the named key.
 // This call requires authentication
-func (r *RepoKeyResource) DeleteName(owner, slug, label string) error {
-	key, err := r.FindName(owner, slug, label)
+func (r *RepoKeyResource) DeleteName(ctx context.Context, owner, slug, label string) error {
+	key, err := r.FindName(ctx, owner, slug, label)
 	if err != nil {
 		return err
 	}
 
-	return r.Delete(owner, slug, key.Id)
+	return r.Delete(ctx, owner, slug, key.Id)
 }
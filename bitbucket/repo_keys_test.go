@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"testing"
 )
 
@@ -11,23 +12,23 @@ func Test_RepoKeys(t *testing.T) {
 	title := "test@localhost"
 
 	// create a new public key
-	key, err := client.RepoKeys.Create(testUser, testRepo, public, title)
+	key, err := client.RepoKeys.Create(context.Background(), testUser, testRepo, public, title)
 	if err != nil {
 		t.Error(err)
 		return
 	}
 
 	// cleanup after ourselves & delete this dummy key
-	defer client.RepoKeys.Delete(testUser, testRepo, key.Id)
+	defer client.RepoKeys.Delete(context.Background(), testUser, testRepo, key.Id)
 
 	// Get the new key we recently created
-	find, err := client.RepoKeys.Find(testUser, testRepo, key.Id)
+	find, err := client.RepoKeys.Find(context.Background(), testUser, testRepo, key.Id)
 	if title != find.Label {
 		t.Errorf("key label [%v]; want [%v]", find.Label, title)
 	}
 
 	// Get a list of SSH keys for the user
-	keys, err := client.RepoKeys.List(testUser, testRepo)
+	keys, err := client.RepoKeys.List(context.Background(), testUser, testRepo)
 	if err != nil {
 		t.Error(err)
 	}
@@ -0,0 +1,246 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// The types of a Code Insights report.
+const (
+	ReportTypeSecurity = "SECURITY"
+	ReportTypeCoverage = "COVERAGE"
+	ReportTypeTest     = "TEST"
+	ReportTypeBug      = "BUG"
+)
+
+// The results of a report or annotation.
+const (
+	ReportResultPassed  = "PASSED"
+	ReportResultFailed  = "FAILED"
+	ReportResultPending = "PENDING"
+	ReportResultSkipped = "SKIPPED"
+	ReportResultIgnored = "IGNORED"
+)
+
+// The types of an annotation.
+const (
+	AnnotationTypeVulnerability = "VULNERABILITY"
+	AnnotationTypeCodeSmell     = "CODE_SMELL"
+	AnnotationTypeBug           = "BUG"
+)
+
+// The severities of an annotation.
+const (
+	SeverityCritical = "CRITICAL"
+	SeverityHigh     = "HIGH"
+	SeverityMedium   = "MEDIUM"
+	SeverityLow      = "LOW"
+)
+
+// MaxAnnotationsPerRequest is the maximum number of annotations
+// Bitbucket accepts in a single request. AddAnnotations splits larger
+// batches into several requests.
+const MaxAnnotationsPerRequest = 100
+
+// Report is a Code Insights report, such as the results of a linter or
+// security scan, attached to a commit.
+type Report struct {
+	// The id of the report, chosen by the reporter. This is
+	// set by Bitbucket from the URL the report is created at.
+	ExternalId string `json:"external_id,omitempty"`
+	UUID       string `json:"uuid,omitempty"`
+
+	Title   string `json:"title"`
+	Details string `json:"details"`
+
+	// One of the ReportType constants.
+	ReportType string `json:"report_type"`
+
+	// One of PASSED, FAILED or PENDING.
+	Result string `json:"result,omitempty"`
+
+	// The name of the tool that produced the report, a link to the full
+	// results, and the URL of a logo to display alongside the report.
+	Reporter string `json:"reporter,omitempty"`
+	Link     string `json:"link,omitempty"`
+	LogoURL  string `json:"logo_url,omitempty"`
+
+	// Up to 10 values summarising the report.
+	Data []*ReportData `json:"data,omitempty"`
+
+	CreatedOn *time.Time `json:"created_on,omitempty"`
+	UpdatedOn *time.Time `json:"updated_on,omitempty"`
+}
+
+// The types of a ReportData value.
+const (
+	ReportDataBoolean    = "BOOLEAN"
+	ReportDataDate       = "DATE"
+	ReportDataDuration   = "DURATION"
+	ReportDataLink       = "LINK"
+	ReportDataNumber     = "NUMBER"
+	ReportDataPercentage = "PERCENTAGE"
+	ReportDataText       = "TEXT"
+)
+
+// ReportData is a single typed value summarising a report, such as the
+// number of issues found. Use the Data functions (ie NumberData) to
+// create values of the correct type.
+type ReportData struct {
+	Title string `json:"title"`
+
+	// One of the ReportData constants.
+	Type string `json:"type"`
+
+	Value interface{} `json:"value"`
+}
+
+// BooleanData creates a BOOLEAN report value.
+func BooleanData(title string, value bool) *ReportData {
+	return &ReportData{Title: title, Type: ReportDataBoolean, Value: value}
+}
+
+// DateData creates a DATE report value.
+func DateData(title string, value time.Time) *ReportData {
+	return &ReportData{Title: title, Type: ReportDataDate, Value: value.UnixNano() / int64(time.Millisecond)}
+}
+
+// DurationData creates a DURATION report value.
+func DurationData(title string, value time.Duration) *ReportData {
+	return &ReportData{Title: title, Type: ReportDataDuration, Value: value.Milliseconds()}
+}
+
+// LinkData creates a LINK report value.
+func LinkData(title, text, href string) *ReportData {
+	return &ReportData{Title: title, Type: ReportDataLink, Value: map[string]string{"text": text, "href": href}}
+}
+
+// NumberData creates a NUMBER report value.
+func NumberData(title string, value float64) *ReportData {
+	return &ReportData{Title: title, Type: ReportDataNumber, Value: value}
+}
+
+// PercentageData creates a PERCENTAGE report value, between 0 and 100.
+func PercentageData(title string, value float64) *ReportData {
+	return &ReportData{Title: title, Type: ReportDataPercentage, Value: value}
+}
+
+// TextData creates a TEXT report value.
+func TextData(title, value string) *ReportData {
+	return &ReportData{Title: title, Type: ReportDataText, Value: value}
+}
+
+// Annotation is an individual finding in a report, optionally
+// anchored to a line of a file.
+type Annotation struct {
+	// The id of the annotation, chosen by the reporter and unique
+	// within the report.
+	ExternalId string `json:"external_id"`
+	UUID       string `json:"uuid,omitempty"`
+
+	// One of the AnnotationType constants.
+	AnnotationType string `json:"annotation_type"`
+
+	// The file and line the annotation applies to, if any.
+	Path string `json:"path,omitempty"`
+	Line int    `json:"line,omitempty"`
+
+	Summary string `json:"summary"`
+	Details string `json:"details,omitempty"`
+
+	// One of PASSED, FAILED, SKIPPED or IGNORED.
+	Result string `json:"result,omitempty"`
+
+	// One of the Severity constants.
+	Severity string `json:"severity,omitempty"`
+
+	Link string `json:"link,omitempty"`
+
+	CreatedOn *time.Time `json:"created_on,omitempty"`
+	UpdatedOn *time.Time `json:"updated_on,omitempty"`
+}
+
+// Use the reports resource to publish Code Insights reports and
+// annotations on the commits of a repository. This resource uses
+// the 2.0 API.
+//
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-reports/
+type ReportResource struct {
+	client *Client
+}
+
+// Gets the reports attached to a commit.
+func (r *ReportResource) List(ctx context.Context, owner, slug, commit string, opts *ListOptions) *Iterator[*Report] {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports", owner, slug, commit)
+	return newIterator[*Report](ctx, r.client, path, nil, opts)
+}
+
+// Gets the report with the given id.
+func (r *ReportResource) Find(ctx context.Context, owner, slug, commit, reportId string) (*Report, error) {
+	report := Report{}
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports/%s", owner, slug, commit, reportId)
+
+	if err := r.client.do2(ctx, "GET", path, nil, nil, &report); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// CreateUpdate creates the report with the given id, replacing it if it
+// already exists. Replacing a report deletes its annotations.
+func (r *ReportResource) CreateUpdate(ctx context.Context, owner, slug, commit, reportId string, report *Report) (*Report, error) {
+	rep := Report{}
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports/%s", owner, slug, commit, reportId)
+
+	if err := r.client.do2(ctx, "PUT", path, nil, report, &rep); err != nil {
+		return nil, err
+	}
+
+	return &rep, nil
+}
+
+// Deletes the report, along with its annotations.
+func (r *ReportResource) Delete(ctx context.Context, owner, slug, commit, reportId string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports/%s", owner, slug, commit, reportId)
+	return r.client.do2(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// Gets the annotations of a report.
+func (r *ReportResource) ListAnnotations(ctx context.Context, owner, slug, commit, reportId string, opts *ListOptions) *Iterator[*Annotation] {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports/%s/annotations", owner, slug, commit, reportId)
+	return newIterator[*Annotation](ctx, r.client, path, nil, opts)
+}
+
+// AddAnnotations creates or replaces annotations on a report, keyed by
+// their ExternalId. The annotations are uploaded in chunks of up to
+// MaxAnnotationsPerRequest. If a chunk fails, the annotations created by
+// the preceding chunks are returned along with the error.
+func (r *ReportResource) AddAnnotations(ctx context.Context, owner, slug, commit, reportId string, annotations []*Annotation) ([]*Annotation, error) {
+	created := []*Annotation{}
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports/%s/annotations", owner, slug, commit, reportId)
+
+	for len(annotations) != 0 {
+		n := len(annotations)
+		if n > MaxAnnotationsPerRequest {
+			n = MaxAnnotationsPerRequest
+		}
+
+		chunk := []*Annotation{}
+		if err := r.client.do2(ctx, "POST", path, nil, annotations[:n], &chunk); err != nil {
+			return created, err
+		}
+
+		created = append(created, chunk...)
+		annotations = annotations[n:]
+	}
+
+	return created, nil
+}
+
+// Deletes an annotation from a report.
+func (r *ReportResource) DeleteAnnotation(ctx context.Context, owner, slug, commit, reportId, annotationId string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports/%s/annotations/%s", owner, slug, commit, reportId, annotationId)
+	return r.client.do2(ctx, "DELETE", path, nil, nil, nil)
+}
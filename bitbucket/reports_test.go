@@ -0,0 +1,96 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_Reports(t *testing.T) {
+	const path = "/2.0/repositories/marcus/project-x/commit/620ade18607a/reports/lint"
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != path {
+			t.Errorf("unexpected request [%v %v]", r.Method, r.URL.Path)
+		}
+		body := decodeBody(t, r)
+		data, _ := body["data"].([]interface{})
+		if len(data) != 2 {
+			t.Errorf("report data [%v]; want 2 values", data)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		duration, ok := data[1].(map[string]interface{})
+		if !ok || duration["type"] != ReportDataDuration || duration["value"] != 1500.0 {
+			t.Errorf("duration data [%v]; want [%v] [%v]", duration, ReportDataDuration, 1500)
+		}
+		if _, ok := body["created_on"]; ok {
+			t.Errorf("expected created_on to be omitted; got %v", body)
+		}
+		w.Write([]byte(`{"external_id": "lint", "uuid": "{r1}", "title": "Lint", "report_type": "BUG", "result": "FAILED"}`))
+	})
+
+	report, err := c.Reports.CreateUpdate(context.Background(), "marcus", "project-x", "620ade18607a", "lint", &Report{
+		Title:      "Lint",
+		ReportType: ReportTypeBug,
+		Result:     ReportResultFailed,
+		Data: []*ReportData{
+			NumberData("Issues", 3),
+			DurationData("Duration", 1500*time.Millisecond),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ExternalId != "lint" || report.UUID != "{r1}" {
+		t.Errorf("report [%v] [%v]", report.ExternalId, report.UUID)
+	}
+}
+
+func Test_ReportsAddAnnotations(t *testing.T) {
+	var chunks []int
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := ioutil.ReadAll(r.Body)
+		annotations := []*Annotation{}
+		if err := json.Unmarshal(raw, &annotations); err != nil {
+			t.Errorf("invalid request body %s: %v", raw, err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		chunks = append(chunks, len(annotations))
+		if len(chunks) == 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, a := range annotations {
+			a.UUID = "{" + a.ExternalId + "}"
+		}
+		json.NewEncoder(w).Encode(annotations)
+	})
+
+	annotations := []*Annotation{}
+	for i := 0; i < 250; i++ {
+		annotations = append(annotations, &Annotation{
+			ExternalId:     fmt.Sprintf("issue-%d", i),
+			AnnotationType: AnnotationTypeCodeSmell,
+			Path:           "somefile.py",
+			Line:           i + 1,
+			Summary:        "unused variable",
+			Severity:       SeverityLow,
+		})
+	}
+
+	created, err := c.Reports.AddAnnotations(context.Background(), "marcus", "project-x", "620ade18607a", "lint", annotations)
+	if err == nil {
+		t.Errorf("expected the third chunk to fail")
+	}
+	if len(chunks) != 3 || chunks[0] != 100 || chunks[1] != 100 || chunks[2] != 50 {
+		t.Errorf("chunk sizes %v; want [100 100 50]", chunks)
+	}
+	if len(created) != 200 || created[199].UUID != "{issue-199}" {
+		t.Errorf("expected the first 200 annotations to be created; got %v", len(created))
+	}
+}
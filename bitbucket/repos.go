@@ -1,19 +1,287 @@
 package bitbucket
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The fork policies of a repository.
+const (
+	ForkPolicyAllowForks    = "allow_forks"
+	ForkPolicyNoPublicForks = "no_public_forks"
+	ForkPolicyNoForks       = "no_forks"
 )
 
 type Repo struct {
-	Name     string `json:"name"`
-	Slug     string `json:"slug"`
-	Owner    string `json:"owner"`
-	Scm      string `json:"scm"`
-	Logo     string `json:"logo"`
-	Language string `json:"language"`
-	Private  bool   `json:"is_private"`
-	IsFork   bool   `json:"is_fork"`
-	ForkOf   *Repo  `json:"fork_of"`
+	UUID        string `json:"uuid"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Owner       string `json:"owner"`
+	FullName    string `json:"full_name"` // The owner and slug, ie "marcus/project-x"
+	Description string `json:"description"`
+	Website     string `json:"website"`
+	Scm         string `json:"scm"`
+	Logo        string `json:"logo"`
+	Language    string `json:"language"`
+	Private     bool   `json:"is_private"`
+	IsFork      bool   `json:"is_fork"`
+	ForkOf      *Repo  `json:"fork_of"`
+
+	// One of the ForkPolicy constants.
+	ForkPolicy string `json:"fork_policy"`
+
+	HasIssues bool `json:"has_issues"`
+	HasWiki   bool `json:"has_wiki"`
+
+	// The size of the repository, in bytes.
+	Size int64 `json:"size"`
+
+	// The name of the main branch. This is not provided by
+	// ListDashboard, which uses the 1.0 API.
+	MainBranch string `json:"mainbranch"`
+
+	// The project and workspace the repository belongs to. These are
+	// not provided by ListDashboard, which uses the 1.0 API.
+	Project   *Project   `json:"project,omitempty"`
+	Workspace *Workspace `json:"workspace,omitempty"`
+
+	// The URLs used to clone the repository.
+	CloneHTTPS string `json:"clone_https"`
+	CloneSSH   string `json:"clone_ssh"`
+
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+
+	Links *Links `json:"links,omitempty"`
+}
+
+// Project is a group of repositories within a workspace.
+type Project struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	UUID string `json:"uuid"`
+}
+
+// Workspace is the account (user or team) that owns repositories
+// and projects.
+type Workspace struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	UUID string `json:"uuid"`
+}
+
+// UnmarshalJSON decodes a Repo from the 1.0 API, the 2.0 API or a webhook
+// payload, populating the same fields whichever format it is given.
+func (r *Repo) UnmarshalJSON(data []byte) error {
+	type repo Repo // (without this method, to avoid recursion)
+	aux := struct {
+		*repo
+
+		// fields with a different type or format in each API
+		Owner      json.RawMessage `json:"owner"`
+		MainBranch json.RawMessage `json:"mainbranch"`
+		CreatedOn  string          `json:"created_on"`
+		UpdatedOn  string          `json:"updated_on"`
+
+		// 1.0 fields
+		UTCCreatedOn   string `json:"utc_created_on"`
+		UTCLastUpdated string `json:"utc_last_updated"`
+		LastUpdated    string `json:"last_updated"`
+		NoForks        bool   `json:"no_forks"`
+		NoPublicForks  bool   `json:"no_public_forks"`
+		AbsoluteURL    string `json:"absolute_url"`
+
+		// 2.0 fields
+		Parent *Repo `json:"parent"`
+	}{repo: (*repo)(r)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	// 1.0 gives the owner's username, 2.0 gives the owner's account
+	owner := User{}
+	if len(aux.Owner) != 0 && aux.Owner[0] == '"' {
+		if err := json.Unmarshal(aux.Owner, &r.Owner); err != nil {
+			return err
+		}
+	} else if len(aux.Owner) != 0 && aux.Owner[0] == '{' {
+		if err := json.Unmarshal(aux.Owner, &owner); err != nil {
+			return err
+		}
+	}
+
+	// 2.0 (and webhook payloads) may omit the slug, and don't give the
+	// owner's username, so we'll take them from the full name
+	if len(r.FullName) == 0 && len(aux.AbsoluteURL) != 0 {
+		r.FullName = strings.Trim(aux.AbsoluteURL, "/")
+	}
+	if i := strings.Index(r.FullName, "/"); i > 0 {
+		if len(r.Owner) == 0 {
+			r.Owner = r.FullName[:i]
+		}
+		if len(r.Slug) == 0 {
+			r.Slug = r.FullName[i+1:]
+		}
+	}
+	if len(r.Owner) == 0 {
+		r.Owner = owner.Username
+	}
+	if len(r.Owner) == 0 {
+		r.Owner = owner.Nickname
+	}
+	if len(r.FullName) == 0 && len(r.Owner) != 0 && len(r.Slug) != 0 {
+		r.FullName = r.Owner + "/" + r.Slug
+	}
+
+	// 2.0 gives the main branch as a ref
+	if len(aux.MainBranch) != 0 && aux.MainBranch[0] == '{' {
+		ref := struct {
+			Name string `json:"name"`
+		}{}
+		if err := json.Unmarshal(aux.MainBranch, &ref); err != nil {
+			return err
+		}
+		r.MainBranch = ref.Name
+	} else if len(aux.MainBranch) != 0 && aux.MainBranch[0] == '"' {
+		if err := json.Unmarshal(aux.MainBranch, &r.MainBranch); err != nil {
+			return err
+		}
+	}
+
+	// 1.0 gives local and UTC timestamps without a "T", 2.0 gives RFC 3339
+	r.CreatedOn = parseRepoTime(aux.UTCCreatedOn, aux.CreatedOn)
+	r.UpdatedOn = parseRepoTime(aux.UTCLastUpdated, aux.UpdatedOn, aux.LastUpdated)
+
+	// 1.0 gives the fork policy as a pair of flags
+	if len(r.ForkPolicy) == 0 {
+		switch {
+		case aux.NoForks:
+			r.ForkPolicy = ForkPolicyNoForks
+		case aux.NoPublicForks:
+			r.ForkPolicy = ForkPolicyNoPublicForks
+		case len(aux.UTCCreatedOn) != 0:
+			r.ForkPolicy = ForkPolicyAllowForks
+		}
+	}
+
+	// 2.0 gives the repository forked from as the parent
+	if aux.Parent != nil {
+		r.IsFork = true
+		r.ForkOf = aux.Parent
+	}
+
+	// 2.0 gives the clone URLs and logo as links, for 1.0 we'll have
+	// to construct the clone URLs ourselves
+	if r.Links != nil {
+		for _, link := range r.Links.Clone {
+			switch link.Name {
+			case "https":
+				r.CloneHTTPS = link.Href
+			case "ssh":
+				r.CloneSSH = link.Href
+			}
+		}
+		if r.Links.Avatar != nil && len(r.Logo) == 0 {
+			r.Logo = r.Links.Avatar.Href
+		}
+	}
+	if len(r.CloneHTTPS) == 0 && len(r.FullName) != 0 && (r.Scm == "git" || len(r.Scm) == 0) {
+		r.CloneHTTPS = fmt.Sprintf("https://bitbucket.org/%s.git", r.FullName)
+		r.CloneSSH = fmt.Sprintf("git@bitbucket.org:%s.git", r.FullName)
+	}
+
+	return nil
+}
+
+// repoTimeFormats are the formats of the timestamps returned
+// by the 1.0 and 2.0 APIs.
+var repoTimeFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02 15:04:05",
+}
+
+// parseRepoTime parses the first of the values that is a valid
+// timestamp, in UTC.
+func parseRepoTime(values ...string) time.Time {
+	for _, v := range values {
+		for _, layout := range repoTimeFormats {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t.UTC()
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// RepoOptions describes a repository to create, or the settings
+// to change on an existing repository.
+type RepoOptions struct {
+	// The display name of the repository. The slug is used if empty.
+	Name string
+
+	// The type of repository. Only "git" is supported.
+	Scm string
+
+	Description string
+	Language    string
+
+	// The key of the project to put the repository in. The workspace's
+	// default project is used if empty.
+	Project string
+
+	// One of the ForkPolicy constants.
+	ForkPolicy string
+
+	// Settings that are left unchanged when nil. Use Bool to
+	// set them.
+	IsPrivate *bool
+	HasIssues *bool
+	HasWiki   *bool
+}
+
+// Bool returns a pointer to the value, for use with the optional
+// fields of RepoOptions.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// body converts the options to the JSON structure expected by
+// Bitbucket, omitting any fields that are not set.
+func (o *RepoOptions) body() map[string]interface{} {
+	body := map[string]interface{}{}
+	if o == nil {
+		return body
+	}
+	for k, v := range map[string]string{
+		"name":        o.Name,
+		"scm":         o.Scm,
+		"description": o.Description,
+		"language":    o.Language,
+		"fork_policy": o.ForkPolicy,
+	} {
+		if len(v) != 0 {
+			body[k] = v
+		}
+	}
+	for k, v := range map[string]*bool{
+		"is_private": o.IsPrivate,
+		"has_issues": o.HasIssues,
+		"has_wiki":   o.HasWiki,
+	} {
+		if v != nil {
+			body[k] = *v
+		}
+	}
+	if len(o.Project) != 0 {
+		body["project"] = map[string]string{"key": o.Project}
+	}
+	return body
 }
 
 type Branch struct {
@@ -36,23 +304,18 @@ type RepoResource struct {
 }
 
 // Gets the repositories owned by the individual or team account.
-func (r *RepoResource) List() ([]*Repo, error) {
-	repos := []*Repo{}
-	const path = "/user/repositories"
-
-	if err := r.client.do("GET", path, nil, nil, &repos); err != nil {
-		return nil, err
-	}
-
-	return repos, nil
+// This uses the 2.0 API, fetching every page.
+func (r *RepoResource) List(ctx context.Context) ([]*Repo, error) {
+	params := url.Values{"role": {"owner"}}
+	return newIterator[*Repo](ctx, r.client, "/repositories", params, nil).All()
 }
 
 // Gets the repositories list from the account's dashboard.
-func (r *RepoResource) ListDashboard() ([]*Account, error) {
+func (r *RepoResource) ListDashboard(ctx context.Context) ([]*Account, error) {
 	var m [][]interface{}
 	const path = "/user/repositories/dashboard"
 
-	if err := r.client.do("GET", path, nil, nil, &m); err != nil {
+	if err := r.client.do(ctx, "GET", path, nil, nil, &m); err != nil {
 		return nil, err
 	}
 
@@ -62,8 +325,8 @@ func (r *RepoResource) ListDashboard() ([]*Account, error) {
 // Gets the repositories list from the account's dashboard, and
 // converts the response to a list of Repos, instead of a
 // list of Accounts.
-func (r *RepoResource) ListDashboardRepos() ([]*Repo, error) {
-	accounts, err := r.ListDashboard()
+func (r *RepoResource) ListDashboardRepos(ctx context.Context) ([]*Repo, error) {
+	accounts, err := r.ListDashboard(ctx)
 	if err != nil {
 		return nil, nil
 	}
@@ -76,12 +339,15 @@ func (r *RepoResource) ListDashboardRepos() ([]*Repo, error) {
 	return repos, nil
 }
 
-// Gets the list of Branches for the repository
-func (r *RepoResource) ListBranches(owner, slug string) ([]*Branch, error) {
+// Gets the list of Branches for the repository, in no particular order.
+//
+// Deprecated: use RefResource.ListBranches, which can sort and filter
+// the branches.
+func (r *RepoResource) ListBranches(ctx context.Context, owner, slug string) ([]*Branch, error) {
 	branchMap := map[string]*Branch{}
 	path := fmt.Sprintf("/repositories/%s/%s/branches", owner, slug)
 
-	if err := r.client.do("GET", path, nil, nil, &branchMap); err != nil {
+	if err := r.client.do(ctx, "GET", path, nil, nil, &branchMap); err != nil {
 		return nil, err
 	}
 
@@ -95,24 +361,72 @@ func (r *RepoResource) ListBranches(owner, slug string) ([]*Branch, error) {
 	return branches, nil
 }
 
-// Gets the repositories list for the named user.
-func (r *RepoResource) ListUser(owner string) ([]*Repo, error) {
-	repos := []*Repo{}
+// Gets the repositories list for the named user. This uses the
+// 2.0 API, fetching every page.
+func (r *RepoResource) ListUser(ctx context.Context, owner string) ([]*Repo, error) {
 	path := fmt.Sprintf("/repositories/%s", owner)
+	return newIterator[*Repo](ctx, r.client, path, nil, nil).All()
+}
 
-	if err := r.client.do("GET", path, nil, nil, &repos); err != nil {
+// Gets the named repository. This uses the 2.0 API.
+func (r *RepoResource) Find(ctx context.Context, owner, slug string) (*Repo, error) {
+	repo := Repo{}
+	path := fmt.Sprintf("/repositories/%s/%s", owner, slug)
+
+	if err := r.client.do2(ctx, "GET", path, nil, nil, &repo); err != nil {
 		return nil, err
 	}
 
-	return repos, nil
+	return &repo, nil
+}
+
+// Creates a repository. This uses the 2.0 API.
+func (r *RepoResource) Create(ctx context.Context, owner, slug string, opts *RepoOptions) (*Repo, error) {
+	repo := Repo{}
+	path := fmt.Sprintf("/repositories/%s/%s", owner, slug)
+
+	if err := r.client.do2(ctx, "POST", path, nil, opts.body(), &repo); err != nil {
+		return nil, err
+	}
+
+	return &repo, nil
 }
 
-// Gets the named repository.
-func (r *RepoResource) Find(owner, slug string) (*Repo, error) {
+// Updates the settings of a repository. Only the options that are set
+// are changed. This uses the 2.0 API.
+func (r *RepoResource) Update(ctx context.Context, owner, slug string, opts *RepoOptions) (*Repo, error) {
 	repo := Repo{}
 	path := fmt.Sprintf("/repositories/%s/%s", owner, slug)
 
-	if err := r.client.do("GET", path, nil, nil, &repo); err != nil {
+	if err := r.client.do2(ctx, "PUT", path, nil, opts.body(), &repo); err != nil {
+		return nil, err
+	}
+
+	return &repo, nil
+}
+
+// Deletes a repository. If redirectTo is not empty, Bitbucket redirects
+// requests for the deleted repository to that URL. This uses the 2.0 API.
+func (r *RepoResource) Delete(ctx context.Context, owner, slug, redirectTo string) error {
+	var params url.Values
+	if len(redirectTo) != 0 {
+		params = url.Values{"redirect_to": {redirectTo}}
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s", owner, slug)
+	return r.client.do2(ctx, "DELETE", path, params, nil, nil)
+}
+
+// Forks a repository into the workspace. The options may be nil, in which
+// case the fork has the same name and settings as the original. This uses
+// the 2.0 API.
+func (r *RepoResource) Fork(ctx context.Context, owner, slug, workspace string, opts *RepoOptions) (*Repo, error) {
+	body := opts.body()
+	body["workspace"] = map[string]string{"slug": workspace}
+
+	repo := Repo{}
+	path := fmt.Sprintf("/repositories/%s/%s/forks", owner, slug)
+	if err := r.client.do2(ctx, "POST", path, nil, body, &repo); err != nil {
 		return nil, err
 	}
 
@@ -164,26 +478,11 @@ func unmarshalUser(m map[string]interface{}) *User {
 }
 
 func unmarshalRepo(m map[string]interface{}) *Repo {
+	// round trip through JSON, so the Repo is populated the
+	// same way as when it is returned by Find
 	r := Repo{}
-	for k, v := range m {
-		// make sure v.(type) is correct type each time?
-		switch k {
-		case "name":
-			r.Name = v.(string)
-		case "slug":
-			r.Slug = v.(string)
-		case "owner":
-			r.Owner = v.(string)
-		case "scm":
-			r.Scm = v.(string)
-		case "logo":
-			r.Logo = v.(string)
-		case "language":
-			r.Language = v.(string)
-		case "is_private":
-			r.Private = v.(bool)
-		}
-
+	if b, err := json.Marshal(m); err == nil {
+		json.Unmarshal(b, &r)
 	}
 	return &r
 }
@@ -1,13 +1,17 @@
 package bitbucket
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
 	"testing"
+	"time"
 )
 
 func Test_Repos(t *testing.T) {
 
 	// LIST of repositories
-	repos, err := client.Repos.List()
+	repos, err := client.Repos.List(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -17,7 +21,7 @@ func Test_Repos(t *testing.T) {
 	}
 	
 	// LIST dashboard repositories
-	accts, err := client.Repos.ListDashboard()
+	accts, err := client.Repos.ListDashboard(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
@@ -27,7 +31,7 @@ func Test_Repos(t *testing.T) {
 	}
 	
 	// FIND the named repo
-	repo, err := client.Repos.Find(testUser, testRepo)
+	repo, err := client.Repos.Find(context.Background(), testUser, testRepo)
 	if err != nil {
 		t.Error(err)
 	}
@@ -36,3 +40,234 @@ func Test_Repos(t *testing.T) {
 		t.Errorf("repo slug [%v]; want [%v]", repo.Slug, testRepo)
 	}
 }
+
+func Test_ReposCreate(t *testing.T) {
+	var requests []string
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.String())
+		switch r.Method {
+		case "POST", "PUT":
+			body := decodeBody(t, r)
+			if r.URL.Path == "/2.0/repositories/marcus/project-x/forks" {
+				if workspace, ok := body["workspace"].(map[string]interface{}); !ok || workspace["slug"] != "jane" {
+					t.Errorf("fork workspace [%v]; want [%v]", body["workspace"], "jane")
+				}
+			} else if r.Method == "POST" {
+				if body["scm"] != "git" || body["is_private"] != true || body["has_wiki"] != false {
+					t.Errorf("create body [%v]", body)
+				}
+				if project, ok := body["project"].(map[string]interface{}); !ok || project["key"] != "PX" {
+					t.Errorf("project [%v]; want [%v]", body["project"], "PX")
+				}
+				if _, ok := body["has_issues"]; ok {
+					t.Errorf("expected has_issues to be omitted; got %v", body)
+				}
+			} else if len(body) != 1 || body["description"] != "New description" {
+				t.Errorf("update body [%v]; want only the description", body)
+			}
+			w.Write([]byte(sampleRepo2))
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	ctx := context.Background()
+
+	// CREATE
+	repo, err := c.Repos.Create(ctx, "marcus", "project-x", &RepoOptions{
+		Scm:       "git",
+		Project:   "PX",
+		IsPrivate: Bool(true),
+		HasWiki:   Bool(false),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Slug != "project-x" || repo.Owner != "marcus" || !repo.Private {
+		t.Errorf("repo slug [%v] owner [%v] private [%v]", repo.Slug, repo.Owner, repo.Private)
+	}
+	if !repo.IsFork || repo.ForkOf.Owner != "atlassian" {
+		t.Errorf("expected repo to be a fork of atlassian/project-x; got %v", repo.ForkOf)
+	}
+
+	// UPDATE
+	if _, err := c.Repos.Update(ctx, "marcus", "project-x", &RepoOptions{Description: "New description"}); err != nil {
+		t.Error(err)
+	}
+
+	// FORK
+	if _, err := c.Repos.Fork(ctx, "marcus", "project-x", "jane", nil); err != nil {
+		t.Error(err)
+	}
+
+	// DELETE with a redirect
+	if err := c.Repos.Delete(ctx, "marcus", "project-x", "https://bitbucket.org/jane/project-x"); err != nil {
+		t.Error(err)
+	}
+
+	want := "DELETE /2.0/repositories/marcus/project-x?redirect_to=https%3A%2F%2Fbitbucket.org%2Fjane%2Fproject-x"
+	if len(requests) != 4 || requests[3] != want {
+		t.Errorf("requests %v; want last [%v]", requests, want)
+	}
+}
+
+var sampleRepo2 = `
+{
+    "type": "repository",
+    "full_name": "marcus/project-x",
+    "name": "Project X",
+    "slug": "project-x",
+    "uuid": "{e5f6}",
+    "scm": "git",
+    "is_private": true,
+    "description": "The next big thing",
+    "website": "https://example.com",
+    "language": "go",
+    "size": 2048,
+    "fork_policy": "no_public_forks",
+    "has_issues": true,
+    "has_wiki": false,
+    "created_on": "2011-12-20T16:35:06.480042+00:00",
+    "updated_on": "2012-01-05T09:10:11.000000+00:00",
+    "mainbranch": {"type": "branch", "name": "main"},
+    "project": {"type": "project", "key": "PX", "name": "Project X", "uuid": "{c3d4}"},
+    "workspace": {"type": "workspace", "slug": "marcus", "name": "Marcus Bertrand", "uuid": "{a1b2}"},
+    "links": {
+        "avatar": {"href": "https://bytebucket.org/ravatar/%7Be5f6%7D"},
+        "clone": [
+            {"name": "https", "href": "https://marcus@bitbucket.org/marcus/project-x.git"},
+            {"name": "ssh", "href": "git@bitbucket.org:marcus/project-x.git"}
+        ]
+    },
+    "owner": {"type": "user", "nickname": "marcus", "display_name": "Marcus Bertrand", "uuid": "{a1b2}"},
+    "parent": {"full_name": "atlassian/project-x", "slug": "project-x", "owner": {"username": "atlassian"}}
+}
+`
+
+func Test_RepoUnmarshal(t *testing.T) {
+	created := time.Date(2011, 12, 20, 16, 35, 6, 480042000, time.UTC)
+
+	// 2.0
+	v2 := Repo{}
+	if err := json.Unmarshal([]byte(sampleRepo2), &v2); err != nil {
+		t.Fatal(err)
+	}
+	if v2.FullName != "marcus/project-x" || v2.UUID != "{e5f6}" || v2.Size != 2048 || v2.Website != "https://example.com" {
+		t.Errorf("repo full name [%v] uuid [%v] size [%v] website [%v]", v2.FullName, v2.UUID, v2.Size, v2.Website)
+	}
+	if !v2.CreatedOn.Equal(created) {
+		t.Errorf("created on [%v]; want [%v]", v2.CreatedOn, created)
+	}
+	if v2.MainBranch != "main" || v2.Project.Key != "PX" || v2.Workspace.Slug != "marcus" {
+		t.Errorf("main branch [%v] project [%v] workspace [%v]", v2.MainBranch, v2.Project, v2.Workspace)
+	}
+	if v2.CloneSSH != "git@bitbucket.org:marcus/project-x.git" || v2.Logo != "https://bytebucket.org/ravatar/%7Be5f6%7D" {
+		t.Errorf("clone ssh [%v] logo [%v]", v2.CloneSSH, v2.Logo)
+	}
+	if v2.ForkPolicy != ForkPolicyNoPublicForks || !v2.HasIssues || v2.HasWiki {
+		t.Errorf("fork policy [%v] issues [%v] wiki [%v]", v2.ForkPolicy, v2.HasIssues, v2.HasWiki)
+	}
+
+	// 1.0
+	v1 := Repo{}
+	if err := json.Unmarshal([]byte(sampleRepo1), &v1); err != nil {
+		t.Fatal(err)
+	}
+	if v1.FullName != "marcus/project-x" || v1.Owner != "marcus" || v1.Description != "The next big thing" {
+		t.Errorf("repo full name [%v] owner [%v] description [%v]", v1.FullName, v1.Owner, v1.Description)
+	}
+	if !v1.CreatedOn.Equal(created.Truncate(time.Second)) {
+		t.Errorf("created on [%v]; want [%v]", v1.CreatedOn, created.Truncate(time.Second))
+	}
+	if v1.CloneHTTPS != "https://bitbucket.org/marcus/project-x.git" || v1.CloneSSH != v2.CloneSSH {
+		t.Errorf("clone https [%v] ssh [%v]", v1.CloneHTTPS, v1.CloneSSH)
+	}
+	if v1.ForkPolicy != ForkPolicyNoPublicForks || !v1.HasIssues || v1.Size != 2048 {
+		t.Errorf("fork policy [%v] issues [%v] size [%v]", v1.ForkPolicy, v1.HasIssues, v1.Size)
+	}
+
+	// the dashboard
+	m := map[string]interface{}{}
+	json.Unmarshal([]byte(sampleRepo1), &m)
+	if dash := unmarshalRepo(m); dash.FullName != v1.FullName || !dash.CreatedOn.Equal(v1.CreatedOn) {
+		t.Errorf("dashboard repo [%v]; want [%v]", dash, v1)
+	}
+
+	// a webhook
+	hook, err := ParseHook([]byte(sampleHook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hook.Repo.FullName != "marcus/project-x" || hook.Repo.CloneHTTPS != v1.CloneHTTPS {
+		t.Errorf("hook repo full name [%v] clone https [%v]", hook.Repo.FullName, hook.Repo.CloneHTTPS)
+	}
+}
+
+var sampleRepo1 = `
+{
+    "scm": "git",
+    "has_wiki": false,
+    "last_updated": "2012-01-05 10:10:11",
+    "no_forks": false,
+    "created_on": "2011-12-20 17:35:06",
+    "owner": "marcus",
+    "logo": "https://d3oaxc4q5k2d6q.cloudfront.net/m/repo-avatar.png",
+    "size": 2048,
+    "is_private": true,
+    "has_issues": true,
+    "website": "",
+    "slug": "project-x",
+    "no_public_forks": true,
+    "description": "The next big thing",
+    "language": "go",
+    "fork_of": null,
+    "utc_last_updated": "2012-01-05 09:10:11+00:00",
+    "utc_created_on": "2011-12-20 16:35:06+00:00",
+    "is_fork": false,
+    "resource_uri": "/1.0/repositories/marcus/project-x",
+    "name": "Project X"
+}
+`
+
+func Test_ReposFind(t *testing.T) {
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2.0/repositories/marcus/project-x":
+			w.Write([]byte(sampleRepo2))
+		case "/2.0/repositories/marcus":
+			// the second page
+			if r.URL.Query().Get("page") == "2" {
+				w.Write([]byte(`{"values": [` + sampleRepo2 + `]}`))
+				return
+			}
+			w.Write([]byte(`{"values": [` + sampleRepo2 + `], "next": "http://` + r.Host + `/2.0/repositories/marcus?page=2"}`))
+		default:
+			t.Errorf("unexpected request [%v %v]", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ctx := context.Background()
+
+	// FIND fills in the fields only provided by the 2.0 API
+	repo, err := c.Repos.Find(ctx, "marcus", "project-x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.UUID != "{e5f6}" || repo.MainBranch != "main" || repo.ForkPolicy != ForkPolicyNoPublicForks {
+		t.Errorf("repo uuid [%v] main branch [%v] fork policy [%v]", repo.UUID, repo.MainBranch, repo.ForkPolicy)
+	}
+	if repo.Project == nil || repo.Project.Key != "PX" || repo.Workspace == nil || repo.Workspace.Slug != "marcus" {
+		t.Errorf("repo project [%v] workspace [%v]", repo.Project, repo.Workspace)
+	}
+	if repo.CloneHTTPS != "https://marcus@bitbucket.org/marcus/project-x.git" || repo.CloneSSH != "git@bitbucket.org:marcus/project-x.git" {
+		t.Errorf("repo clone https [%v] ssh [%v]", repo.CloneHTTPS, repo.CloneSSH)
+	}
+
+	// LIST fetches every page
+	repos, err := c.Repos.ListUser(ctx, "marcus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 2 || repos[1].FullName != "marcus/project-x" {
+		t.Errorf("repos %v; want 2", repos)
+	}
+}
@@ -0,0 +1,221 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// The kinds of branch restriction.
+const (
+	// Only the exempted users and groups may push to the branch.
+	RestrictionKindPush = "push"
+
+	// Prevents rewriting the history of the branch.
+	RestrictionKindForcePush = "force"
+
+	// Prevents deleting the branch.
+	RestrictionKindDelete = "delete"
+
+	// Only the exempted users and groups may merge pull requests
+	// into the branch.
+	RestrictionKindRestrictMerges = "restrict_merges"
+
+	// Requires Value approvals before a pull request can be merged.
+	RestrictionKindRequireApprovals = "require_approvals_to_merge"
+
+	// Requires Value approvals from default reviewers before a pull
+	// request can be merged.
+	RestrictionKindRequireDefaultReviewerApprovals = "require_default_reviewer_approvals_to_merge"
+
+	// Requires Value successful builds, and no failed builds, before
+	// a pull request can be merged.
+	RestrictionKindRequirePassingBuilds = "require_passing_builds_to_merge"
+
+	// Requires every task to be resolved before a pull request can
+	// be merged.
+	RestrictionKindRequireTasksCompleted = "require_tasks_to_be_completed"
+
+	// Requires that no reviewer has requested changes before a pull
+	// request can be merged.
+	RestrictionKindRequireNoChangesRequested = "require_no_changes_requested"
+
+	// Resets the approvals of a pull request when its source
+	// branch changes.
+	RestrictionKindResetApprovalsOnChange = "reset_pullrequest_approvals_on_change"
+
+	// Prevents merging a pull request until the other merge checks
+	// pass, rather than just warning about them.
+	RestrictionKindEnforceMergeChecks = "enforce_merge_checks"
+)
+
+// The ways a branch restriction selects the branches it applies to.
+const (
+	// Matches branches by the glob in Pattern, ie "release/*".
+	BranchMatchKindGlob = "glob"
+
+	// Matches branches by their type, as set by BranchType, in the
+	// repository's branching model.
+	BranchMatchKindBranchingModel = "branching_model"
+)
+
+// The types of branch in a branching model.
+const (
+	BranchTypeFeature     = "feature"
+	BranchTypeBugfix      = "bugfix"
+	BranchTypeRelease     = "release"
+	BranchTypeHotfix      = "hotfix"
+	BranchTypeDevelopment = "development"
+	BranchTypeProduction  = "production"
+)
+
+// BranchRestriction restricts what can be done to the branches of a
+// repository, or sets the conditions for merging pull requests into
+// them.
+type BranchRestriction struct {
+	Id int `json:"id"`
+
+	// One of the RestrictionKind constants.
+	Kind string `json:"kind"`
+
+	// One of the BranchMatchKind constants. Bitbucket uses glob
+	// if empty.
+	BranchMatchKind string `json:"branch_match_kind"`
+
+	// The glob matching the names of the branches restricted, used
+	// when BranchMatchKind is glob.
+	Pattern string `json:"pattern"`
+
+	// One of the BranchType constants, used when BranchMatchKind is
+	// branching_model.
+	BranchType string `json:"branch_type,omitempty"`
+
+	// The number of approvals or builds required, for the kinds of
+	// restriction that need one.
+	Value int `json:"value,omitempty"`
+
+	// The users and groups exempt from the push and restrict_merges
+	// restrictions. Users are identified by UUID, or by username if the
+	// UUID is empty, and groups by slug.
+	Users  []*User  `json:"users,omitempty"`
+	Groups []*Group `json:"groups,omitempty"`
+
+	Links *Links `json:"links,omitempty"`
+}
+
+// body converts the restriction to the JSON structure expected by
+// Bitbucket, omitting the read-only fields and identifying the
+// exempted users and groups only by their keys.
+func (b *BranchRestriction) body() map[string]interface{} {
+	body := map[string]interface{}{
+		"kind": b.Kind,
+	}
+	if len(b.BranchMatchKind) != 0 {
+		body["branch_match_kind"] = b.BranchMatchKind
+	}
+	if b.BranchMatchKind == BranchMatchKindBranchingModel {
+		body["branch_type"] = b.BranchType
+	} else {
+		body["pattern"] = b.Pattern
+	}
+	if b.Value != 0 {
+		body["value"] = b.Value
+	}
+
+	users := []map[string]string{}
+	for _, u := range b.Users {
+		if len(u.UUID) != 0 {
+			users = append(users, map[string]string{"uuid": u.UUID})
+		} else {
+			users = append(users, map[string]string{"username": u.Username})
+		}
+	}
+	groups := []map[string]string{}
+	for _, g := range b.Groups {
+		groups = append(groups, map[string]string{"slug": g.Slug})
+	}
+	body["users"] = users
+	body["groups"] = groups
+
+	return body
+}
+
+// BranchRestrictionListOptions filters and paginates a listing of
+// branch restrictions.
+type BranchRestrictionListOptions struct {
+	ListOptions
+
+	// Only list restrictions of this kind.
+	Kind string
+
+	// Only list restrictions with this pattern.
+	Pattern string
+}
+
+// Use the branch restrictions resource to manage the branch permissions
+// and merge checks of a repository. This resource uses the 2.0 API.
+//
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-branch-restrictions/
+type BranchRestrictionResource struct {
+	client *Client
+}
+
+// Gets the branch restrictions of a repository.
+func (r *BranchRestrictionResource) List(ctx context.Context, owner, slug string, opts *BranchRestrictionListOptions) *Iterator[*BranchRestriction] {
+	params := url.Values{}
+	var listOpts *ListOptions
+	if opts != nil {
+		if len(opts.Kind) != 0 {
+			params.Set("kind", opts.Kind)
+		}
+		if len(opts.Pattern) != 0 {
+			params.Set("pattern", opts.Pattern)
+		}
+		listOpts = &opts.ListOptions
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/branch-restrictions", owner, slug)
+	return newIterator[*BranchRestriction](ctx, r.client, path, params, listOpts)
+}
+
+// Gets the branch restriction with the given id.
+func (r *BranchRestrictionResource) Find(ctx context.Context, owner, slug string, id int) (*BranchRestriction, error) {
+	restriction := BranchRestriction{}
+	path := fmt.Sprintf("/repositories/%s/%s/branch-restrictions/%v", owner, slug, id)
+
+	if err := r.client.do2(ctx, "GET", path, nil, nil, &restriction); err != nil {
+		return nil, err
+	}
+
+	return &restriction, nil
+}
+
+// Creates a branch restriction.
+func (r *BranchRestrictionResource) Create(ctx context.Context, owner, slug string, restriction *BranchRestriction) (*BranchRestriction, error) {
+	b := BranchRestriction{}
+	path := fmt.Sprintf("/repositories/%s/%s/branch-restrictions", owner, slug)
+
+	if err := r.client.do2(ctx, "POST", path, nil, restriction.body(), &b); err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// Updates an existing branch restriction, identified by its id.
+func (r *BranchRestrictionResource) Update(ctx context.Context, owner, slug string, restriction *BranchRestriction) (*BranchRestriction, error) {
+	b := BranchRestriction{}
+	path := fmt.Sprintf("/repositories/%s/%s/branch-restrictions/%v", owner, slug, restriction.Id)
+
+	if err := r.client.do2(ctx, "PUT", path, nil, restriction.body(), &b); err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// Deletes the branch restriction with the given id.
+func (r *BranchRestrictionResource) Delete(ctx context.Context, owner, slug string, id int) error {
+	path := fmt.Sprintf("/repositories/%s/%s/branch-restrictions/%v", owner, slug, id)
+	return r.client.do2(ctx, "DELETE", path, nil, nil, nil)
+}
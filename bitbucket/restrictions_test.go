@@ -0,0 +1,111 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func Test_BranchRestrictions(t *testing.T) {
+	var requests []string
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.String())
+		switch r.Method + " " + r.URL.Path {
+		case "GET /2.0/repositories/marcus/project-x/branch-restrictions":
+			w.Write([]byte(sampleRestrictions))
+		case "POST /2.0/repositories/marcus/project-x/branch-restrictions":
+			body := decodeBody(t, r)
+			if body["kind"] != RestrictionKindPush || body["branch_type"] != BranchTypeProduction {
+				t.Errorf("restriction body [%v]", body)
+			}
+			if _, ok := body["pattern"]; ok {
+				t.Errorf("expected pattern to be omitted for a branching model restriction; got %v", body)
+			}
+			users, _ := body["users"].([]interface{})
+			groups, _ := body["groups"].([]interface{})
+			if len(users) != 1 || len(groups) != 1 {
+				t.Errorf("users %v groups %v; want only their keys", users, groups)
+			} else if user, ok := users[0].(map[string]interface{}); !ok || user["uuid"] != "{a1b2}" {
+				t.Errorf("users %v groups %v; want only their keys", users, groups)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 3, "kind": "push", "branch_match_kind": "branching_model", "branch_type": "production"}`))
+		case "PUT /2.0/repositories/marcus/project-x/branch-restrictions/2":
+			body := decodeBody(t, r)
+			if body["value"] != float64(2) || body["pattern"] != "master" {
+				t.Errorf("restriction body [%v]", body)
+			}
+			w.Write([]byte(`{"id": 2, "kind": "require_approvals_to_merge", "pattern": "master", "value": 2}`))
+		case "DELETE /2.0/repositories/marcus/project-x/branch-restrictions/1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request [%v %v]", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+
+	// LIST
+	restrictions, err := c.BranchRestrictions.List(ctx, "marcus", "project-x", &BranchRestrictionListOptions{Kind: RestrictionKindRequireApprovals}).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restrictions) != 2 || restrictions[1].Value != 1 || restrictions[0].Users[0].Nickname != "marcus" {
+		t.Errorf("restrictions %v", restrictions)
+	}
+
+	// CREATE
+	created, err := c.BranchRestrictions.Create(ctx, "marcus", "project-x", &BranchRestriction{
+		Kind:            RestrictionKindPush,
+		BranchMatchKind: BranchMatchKindBranchingModel,
+		BranchType:      BranchTypeProduction,
+		Users:           []*User{{UUID: "{a1b2}", Nickname: "marcus"}},
+		Groups:          []*Group{{Slug: "developers", Name: "Developers"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Id != 3 {
+		t.Errorf("restriction id [%v]; want [%v]", created.Id, 3)
+	}
+
+	// UPDATE
+	restrictions[1].Value = 2
+	if _, err := c.BranchRestrictions.Update(ctx, "marcus", "project-x", restrictions[1]); err != nil {
+		t.Error(err)
+	}
+
+	// DELETE
+	if err := c.BranchRestrictions.Delete(ctx, "marcus", "project-x", 1); err != nil {
+		t.Error(err)
+	}
+
+	if len(requests) != 4 || requests[0] != "GET /2.0/repositories/marcus/project-x/branch-restrictions?kind=require_approvals_to_merge" {
+		t.Errorf("requests %v", requests)
+	}
+}
+
+var sampleRestrictions = `
+{
+    "pagelen": 10,
+    "values": [
+        {
+            "id": 1,
+            "kind": "push",
+            "branch_match_kind": "glob",
+            "pattern": "master",
+            "value": null,
+            "users": [{"type": "user", "nickname": "marcus", "uuid": "{a1b2}"}],
+            "groups": [{"slug": "developers", "name": "Developers"}]
+        },
+        {
+            "id": 2,
+            "kind": "require_approvals_to_merge",
+            "branch_match_kind": "glob",
+            "pattern": "master",
+            "value": 1,
+            "users": [],
+            "groups": []
+        }
+    ]
+}
+`
@@ -0,0 +1,197 @@
+package bitbucket
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how a Client retries requests that fail with a
+// transient error, such as a rate limit (429) or a 5xx from Bitbucket.
+//
+// By default only idempotent methods (GET, HEAD, OPTIONS, PUT and DELETE)
+// are retried, since retrying a POST may create a resource twice.
+type RetryPolicy struct {
+	// The maximum number of attempts, including the first. A value
+	// less than 2 disables retries.
+	MaxAttempts int
+
+	// The delay before the first retry, doubling for each subsequent
+	// retry up to MaxBackoff. A random jitter of up to half the delay
+	// is subtracted so that concurrent clients don't retry in lockstep.
+	// A MinBackoff or MaxBackoff of zero uses the DefaultRetryPolicy's.
+	//
+	// MaxBackoff also limits the delay requested by Bitbucket with
+	// the Retry-After or X-RateLimit-Reset headers; when the delay is
+	// longer, the Client retries after MaxBackoff regardless.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Retry non-idempotent methods (POST and PATCH) as well.
+	RetryNonIdempotent bool
+
+	// OnRetry, if set, is called before the Client waits to retry
+	// a request.
+	OnRetry func(*RetryEvent)
+}
+
+// RetryEvent describes a failed attempt that is about to be retried.
+type RetryEvent struct {
+	// The method and URL of the request, with any credentials in the
+	// query string redacted.
+	Method string
+	URL    string
+
+	// The attempt that failed, starting at 1.
+	Attempt int
+
+	// The status code of the response, or zero if no
+	// response was received.
+	StatusCode int
+
+	// The error the attempt failed with.
+	Err error
+
+	// How long the Client will wait before the next attempt.
+	Wait time.Duration
+}
+
+// DefaultRetryPolicy is a reasonable RetryPolicy for batch jobs.
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxAttempts: 4,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+}
+
+// backoff reports whether a failed attempt should be retried, and
+// if so, how long to wait before retrying.
+func (p *RetryPolicy) backoff(method string, attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+	default:
+		if !p.RetryNonIdempotent {
+			return 0, false
+		}
+	}
+
+	// errors from the transport (ie connection reset) are retried,
+	// otherwise only rate limits and server errors are retried
+	if resp != nil {
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+		default:
+			return 0, false
+		}
+
+		// if Bitbucket told us how long to wait, we'll do as we're told,
+		// up to MaxBackoff. The rate limit reset only tells us how long to
+		// wait for a 429; a 5xx is unrelated to the quota.
+		if wait, ok := retryAfter(resp.Header); ok {
+			return p.capWait(wait), true
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			if wait, ok := rateLimitReset(resp.Header); ok {
+				return p.capWait(wait), true
+			}
+		}
+	}
+
+	min := p.MinBackoff
+	if min <= 0 {
+		min = DefaultRetryPolicy.MinBackoff
+	}
+
+	// double the delay for each attempt, stopping at MaxBackoff so that
+	// a large number of attempts can't overflow it
+	max := p.maxBackoff()
+	wait := min
+	for i := 1; i < attempt && wait < max; i++ {
+		if wait > max/2 {
+			wait = max
+			break
+		}
+		wait *= 2
+	}
+	wait = p.capWait(wait)
+	wait -= time.Duration(rand.Int63n(int64(wait)/2 + 1))
+
+	return wait, true
+}
+
+// maxBackoff gets the MaxBackoff, or the DefaultRetryPolicy's if zero.
+func (p *RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return DefaultRetryPolicy.MaxBackoff
+	}
+	return p.MaxBackoff
+}
+
+// capWait limits the wait to MaxBackoff.
+func (p *RetryPolicy) capWait(wait time.Duration) time.Duration {
+	if max := p.maxBackoff(); wait > max {
+		return max
+	}
+	return wait
+}
+
+// retryAfter parses the delay requested by the Retry-After header, which
+// may be a number of seconds or an HTTP date.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	if v := h.Get("Retry-After"); len(v) != 0 {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return nonNegative(time.Until(t)), true
+		}
+	}
+
+	return 0, false
+}
+
+// rateLimitReset parses the time until the rate limit window resets from
+// the X-RateLimit-Reset header (in seconds since the epoch).
+func rateLimitReset(h http.Header) (time.Duration, bool) {
+	if v := h.Get("X-RateLimit-Reset"); len(v) != 0 {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return nonNegative(time.Until(time.Unix(secs, 0))), true
+		}
+	}
+
+	return 0, false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// sleep waits for the duration to elapse or the context to be
+// done, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
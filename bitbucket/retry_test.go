@@ -0,0 +1,190 @@
+package bitbucket
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Retry(t *testing.T) {
+	var auths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auths = append(auths, r.Header.Get("Authorization"))
+		switch len(auths) {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`{"user": {"username": "marcus"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	var events []*RetryEvent
+	policy := &RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  10 * time.Millisecond,
+		OnRetry: func(e *RetryEvent) {
+			events = append(events, e)
+		},
+	}
+
+	auth := &OAuth{ConsumerKey: "key", ConsumerSecret: "secret", AccessToken: "token", TokenSecret: "secret"}
+	c := New(auth, WithBaseURL(srv.URL), WithHTTPClient(srv.Client()), WithRetryPolicy(policy))
+
+	acct, err := c.Users.Current(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acct.User.Username != "marcus" {
+		t.Errorf("username [%v]; want [%v]", acct.User.Username, "marcus")
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("retry events [%v]; want [%v]", len(events), 2)
+	}
+	if events[0].StatusCode != http.StatusBadGateway || events[0].Attempt != 1 {
+		t.Errorf("first retry status [%v] attempt [%v]; want [%v] [%v]", events[0].StatusCode, events[0].Attempt, http.StatusBadGateway, 1)
+	}
+	if events[1].StatusCode != http.StatusTooManyRequests || events[1].Wait != 0 {
+		t.Errorf("second retry status [%v] wait [%v]; want [%v] [%v]", events[1].StatusCode, events[1].Wait, http.StatusTooManyRequests, 0)
+	}
+
+	// each attempt must be signed with a fresh nonce
+	if auths[0] == auths[1] || auths[1] == auths[2] {
+		t.Errorf("expected each attempt to be re-signed; got %v", auths)
+	}
+}
+
+func Test_RetryEventRedacted(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var urls []string
+	policy := &RetryPolicy{
+		MaxAttempts: 2,
+		MinBackoff:  time.Millisecond,
+		OnRetry: func(e *RetryEvent) {
+			urls = append(urls, e.URL)
+		},
+	}
+	c := New(&BasicAuth{}, WithBaseURL(srv.URL), WithHTTPClient(srv.Client()), WithRetryPolicy(policy))
+
+	params := url.Values{"access_token": {"hunter2"}, "page": {"2"}}
+	if err := c.do(context.Background(), "GET", "/user", params, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(urls) != 1 {
+		t.Fatalf("retry events [%v]; want [%v]", len(urls), 1)
+	}
+	if strings.Contains(urls[0], "hunter2") || !strings.Contains(urls[0], "access_token="+redacted) {
+		t.Errorf("retry url [%v]; want the access token redacted", urls[0])
+	}
+}
+
+func Test_RetryNonIdempotent(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	policy := &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+	c := New(&Anonymous{}, WithBaseURL(srv.URL), WithHTTPClient(srv.Client()), WithRetryPolicy(policy))
+
+	if _, err := c.Keys.Create(context.Background(), "marcus", "ssh-rsa AAAA", "test"); err == nil {
+		t.Errorf("expected error creating key")
+	}
+	if requests != 1 {
+		t.Errorf("request count [%v]; want [%v]", requests, 1)
+	}
+}
+
+func Test_RetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 5, MinBackoff: time.Second, MaxBackoff: 3 * time.Second}
+
+	// exponential, with up to half the delay taken off as jitter
+	for attempt, max := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		wait, ok := policy.backoff("GET", attempt+1, nil, context.DeadlineExceeded)
+		if !ok {
+			t.Errorf("expected attempt [%v] to be retried", attempt+1)
+		}
+		if wait < max/2 || wait > max {
+			t.Errorf("attempt [%v] wait [%v]; want between [%v] and [%v]", attempt+1, wait, max/2, max)
+		}
+	}
+
+	// out of attempts
+	if _, ok := policy.backoff("GET", 5, nil, context.DeadlineExceeded); ok {
+		t.Errorf("expected no retry after MaxAttempts")
+	}
+
+	// a Retry-After longer than MaxBackoff is retried after MaxBackoff
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"60"}}}
+	if wait, ok := policy.backoff("GET", 1, resp, nil); !ok || wait != 3*time.Second {
+		t.Errorf("Retry-After 60 wait [%v] retried [%v]; want [%v]", wait, ok, 3*time.Second)
+	}
+
+	// a 429 without a Retry-After waits for the rate limit to reset
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	resp = &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"X-Ratelimit-Reset": {reset}}}
+	if wait, ok := policy.backoff("GET", 1, resp, nil); !ok || wait != 3*time.Second {
+		t.Errorf("429 wait [%v] retried [%v]; want [%v]", wait, ok, 3*time.Second)
+	}
+
+	// but a 5xx backs off exponentially, ignoring the rate limit reset
+	resp = &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"X-Ratelimit-Reset": {reset}}}
+	if wait, ok := policy.backoff("GET", 1, resp, nil); !ok || wait > time.Second {
+		t.Errorf("503 wait [%v] retried [%v]; want at most [%v]", wait, ok, time.Second)
+	}
+
+	// a zero MinBackoff uses the default, rather than MaxBackoff
+	zero := &RetryPolicy{MaxAttempts: 2, MaxBackoff: time.Minute}
+	if wait, _ := zero.backoff("GET", 1, nil, context.DeadlineExceeded); wait > DefaultRetryPolicy.MinBackoff {
+		t.Errorf("zero MinBackoff wait [%v]; want at most [%v]", wait, DefaultRetryPolicy.MinBackoff)
+	}
+
+	// a zero MaxBackoff uses the default, and many attempts don't
+	// overflow the delay
+	many := &RetryPolicy{MaxAttempts: 100}
+	for attempt := 1; attempt < 100; attempt++ {
+		wait, ok := many.backoff("GET", attempt, nil, context.DeadlineExceeded)
+		if !ok || wait <= 0 || wait > DefaultRetryPolicy.MaxBackoff {
+			t.Errorf("attempt [%v] wait [%v] retried [%v]; want between 0 and [%v]", attempt, wait, ok, DefaultRetryPolicy.MaxBackoff)
+		}
+	}
+	huge := &RetryPolicy{MaxAttempts: 100, MaxBackoff: math.MaxInt64}
+	if wait, _ := huge.backoff("GET", 99, nil, context.DeadlineExceeded); wait <= 0 {
+		t.Errorf("attempt [99] wait [%v]; want more than 0", wait)
+	}
+
+	// client errors are not retried
+	resp = &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}}
+	if _, ok := policy.backoff("GET", 1, resp, nil); ok {
+		t.Errorf("expected no retry for a 404")
+	}
+
+	// a nil policy never retries
+	var none *RetryPolicy
+	if _, ok := none.backoff("GET", 1, nil, context.DeadlineExceeded); ok {
+		t.Errorf("expected no retry without a RetryPolicy")
+	}
+}
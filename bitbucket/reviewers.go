@@ -0,0 +1,77 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+)
+
+// The sources of an effective default reviewer.
+const (
+	ReviewerTypeRepository = "repository"
+	ReviewerTypeProject    = "project"
+)
+
+// EffectiveReviewer is a user added as a reviewer to new pull requests,
+// either by the repository or by the project it belongs to.
+type EffectiveReviewer struct {
+	// One of the ReviewerType constants.
+	ReviewerType string `json:"reviewer_type"`
+	User         *User  `json:"user"`
+}
+
+// Use the default reviewers resource to manage the users added as
+// reviewers to every new pull request in a repository. This resource
+// uses the 2.0 API.
+//
+// Users are identified by UUID, ie "{a1b2c3d4-...}", or by username.
+//
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-default-reviewers-get
+type DefaultReviewerResource struct {
+	client *Client
+}
+
+// Gets the default reviewers of a repository. This does not include
+// those inherited from the project; see ListEffective.
+func (r *DefaultReviewerResource) List(ctx context.Context, owner, slug string, opts *ListOptions) *Iterator[*User] {
+	path := fmt.Sprintf("/repositories/%s/%s/default-reviewers", owner, slug)
+	return newIterator[*User](ctx, r.client, path, nil, opts)
+}
+
+// Gets the default reviewers of a repository, including those inherited
+// from the project it belongs to.
+func (r *DefaultReviewerResource) ListEffective(ctx context.Context, owner, slug string, opts *ListOptions) *Iterator[*EffectiveReviewer] {
+	path := fmt.Sprintf("/repositories/%s/%s/effective-default-reviewers", owner, slug)
+	return newIterator[*EffectiveReviewer](ctx, r.client, path, nil, opts)
+}
+
+// Gets the user, if they are a default reviewer of the repository.
+// Returns an error matching ErrNotFound if they are not.
+func (r *DefaultReviewerResource) Find(ctx context.Context, owner, slug, user string) (*User, error) {
+	reviewer := User{}
+	path := fmt.Sprintf("/repositories/%s/%s/default-reviewers/%s", owner, slug, user)
+
+	if err := r.client.do2(ctx, "GET", path, nil, nil, &reviewer); err != nil {
+		return nil, err
+	}
+
+	return &reviewer, nil
+}
+
+// Adds the user as a default reviewer of the repository. Adding an
+// existing default reviewer has no effect.
+func (r *DefaultReviewerResource) Add(ctx context.Context, owner, slug, user string) (*User, error) {
+	reviewer := User{}
+	path := fmt.Sprintf("/repositories/%s/%s/default-reviewers/%s", owner, slug, user)
+
+	if err := r.client.do2(ctx, "PUT", path, nil, nil, &reviewer); err != nil {
+		return nil, err
+	}
+
+	return &reviewer, nil
+}
+
+// Removes the user from the default reviewers of the repository.
+func (r *DefaultReviewerResource) Remove(ctx context.Context, owner, slug, user string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/default-reviewers/%s", owner, slug, user)
+	return r.client.do2(ctx, "DELETE", path, nil, nil, nil)
+}
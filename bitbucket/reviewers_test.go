@@ -0,0 +1,72 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func Test_DefaultReviewers(t *testing.T) {
+	var requests []string
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /2.0/repositories/marcus/project-x/default-reviewers":
+			w.Write([]byte(`{"values": [{"type": "user", "nickname": "marcus", "uuid": "{a1b2}"}]}`))
+		case "GET /2.0/repositories/marcus/project-x/effective-default-reviewers":
+			w.Write([]byte(`{"values": [
+				{"type": "default_reviewer_and_type", "reviewer_type": "repository", "user": {"nickname": "marcus", "uuid": "{a1b2}"}},
+				{"type": "default_reviewer_and_type", "reviewer_type": "project", "user": {"nickname": "jane", "uuid": "{c3d4}"}}
+			]}`))
+		case "GET /2.0/repositories/marcus/project-x/default-reviewers/{c3d4}":
+			w.WriteHeader(http.StatusNotFound)
+		case "PUT /2.0/repositories/marcus/project-x/default-reviewers/{c3d4}":
+			w.Write([]byte(`{"type": "user", "nickname": "jane", "uuid": "{c3d4}"}`))
+		case "DELETE /2.0/repositories/marcus/project-x/default-reviewers/{a1b2}":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request [%v %v]", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+
+	// LIST
+	reviewers, err := c.DefaultReviewers.List(ctx, "marcus", "project-x", nil).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reviewers) != 1 || reviewers[0].UUID != "{a1b2}" {
+		t.Errorf("default reviewers %v", reviewers)
+	}
+
+	// LIST including those inherited from the project
+	effective, err := c.DefaultReviewers.ListEffective(ctx, "marcus", "project-x", nil).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(effective) != 2 || effective[1].ReviewerType != ReviewerTypeProject || effective[1].User.Nickname != "jane" {
+		t.Errorf("effective default reviewers %v", effective)
+	}
+
+	// FIND a user who isn't a default reviewer, and ADD them
+	if _, err := c.DefaultReviewers.Find(ctx, "marcus", "project-x", "{c3d4}"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("find error [%v]; want [%v]", err, ErrNotFound)
+	}
+	added, err := c.DefaultReviewers.Add(ctx, "marcus", "project-x", "{c3d4}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.Nickname != "jane" {
+		t.Errorf("added [%v]; want [%v]", added.Nickname, "jane")
+	}
+
+	// REMOVE
+	if err := c.DefaultReviewers.Remove(ctx, "marcus", "project-x", "{a1b2}"); err != nil {
+		t.Error(err)
+	}
+
+	if len(requests) != 5 {
+		t.Errorf("requests %v", requests)
+	}
+}
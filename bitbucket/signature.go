@@ -0,0 +1,120 @@
+package bitbucket
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrMissingSignature is returned when a webhook delivery has no
+// X-Hub-Signature header, and ErrInvalidSignature when the signature
+// does not match any of the secrets.
+var (
+	ErrMissingSignature = errors.New("missing webhook signature")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// SignatureHeader is the header Bitbucket signs webhook deliveries
+// with, when the webhook has a secret.
+const SignatureHeader = "X-Hub-Signature"
+
+// VerifySignature checks the signature of a webhook payload, in the
+// form "sha256=<hex digest>", is the HMAC-SHA256 of the payload keyed
+// with one of the secrets. Several secrets may be given, so that a
+// webhook's secret can be rotated without rejecting deliveries signed
+// with the old one. Empty secrets are ignored, since anyone can sign a
+// payload with an empty key.
+func VerifySignature(payload []byte, signature string, secrets ...string) error {
+	if len(signature) == 0 {
+		return ErrMissingSignature
+	}
+	if !hasSecret(secrets) {
+		return fmt.Errorf("%w: no secrets configured", ErrInvalidSignature)
+	}
+
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return fmt.Errorf("%w: unsupported algorithm", ErrInvalidSignature)
+	}
+	sig, err := hex.DecodeString(digest)
+	if err != nil {
+		return fmt.Errorf("%w: malformed digest", ErrInvalidSignature)
+	}
+
+	// check every secret, so the time taken doesn't reveal which
+	// secret matched
+	valid := false
+	for _, secret := range secrets {
+		if len(secret) == 0 {
+			continue
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload)
+		if hmac.Equal(sig, mac.Sum(nil)) {
+			valid = true
+		}
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// hasSecret reports whether any of the secrets is not empty.
+func hasSecret(secrets []string) bool {
+	for _, secret := range secrets {
+		if len(secret) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyWebhook reads the body of a webhook delivery and checks its
+// signature with VerifySignature. The body is returned, and replaced so
+// that it can be read again, ie by ParseWebhook.
+func VerifyWebhook(r *http.Request, secrets ...string) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := VerifySignature(body, r.Header.Get(SignatureHeader), secrets...); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// RequireSignature returns a handler that verifies the signature of each
+// webhook delivery with VerifyWebhook before passing it to h. Deliveries
+// that are unsigned, or signed with none of the secrets, are rejected
+// with 401 Unauthorized, and those larger than DefaultMaxBodyBytes with
+// 413 Request Entity Too Large.
+func RequireSignature(h http.Handler, secrets ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, DefaultMaxBodyBytes)
+
+		var tooLarge *http.MaxBytesError
+		if _, err := VerifyWebhook(r, secrets...); err != nil {
+			switch {
+			case errors.As(err, &tooLarge):
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignature):
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+			default:
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			}
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,92 @@
+package bitbucket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func sign(payload, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func Test_VerifySignature(t *testing.T) {
+	const payload = `{"push": {}}`
+	tests := []struct {
+		signature string
+		secrets   []string
+		want      error
+	}{
+		{sign(payload, "new"), []string{"new"}, nil},
+		{sign(payload, "old"), []string{"new", "old"}, nil},
+		{sign(payload, "other"), []string{"new", "old"}, ErrInvalidSignature},
+		{sign(payload+" ", "new"), []string{"new"}, ErrInvalidSignature},
+		{strings.Replace(sign(payload, "new"), "sha256", "sha1", 1), []string{"new"}, ErrInvalidSignature},
+		{"sha256=not-hex", []string{"new"}, ErrInvalidSignature},
+		{sign(payload, "new"), nil, ErrInvalidSignature},
+		{sign(payload, ""), []string{""}, ErrInvalidSignature},
+		{sign(payload, ""), []string{"", "new"}, ErrInvalidSignature},
+		{sign(payload, "new"), []string{"", "new"}, nil},
+		{"", []string{"new"}, ErrMissingSignature},
+	}
+	for _, test := range tests {
+		err := VerifySignature([]byte(payload), test.signature, test.secrets...)
+		if (test.want == nil && err != nil) || !errors.Is(err, test.want) {
+			t.Errorf("signature [%v] secrets %v error [%v]; want [%v]", test.signature, test.secrets, err, test.want)
+		}
+	}
+}
+
+func Test_RequireSignature(t *testing.T) {
+	const payload = `{"push": {}}`
+	h := RequireSignature(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the body should still be readable
+		if body, _ := io.ReadAll(r.Body); string(body) != payload {
+			t.Errorf("body [%s]; want [%s]", body, payload)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}), "new", "old")
+
+	tests := []struct {
+		signature string
+		want      int
+	}{
+		{sign(payload, "old"), http.StatusNoContent},
+		{sign(payload, "other"), http.StatusUnauthorized},
+		{"", http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/hook", strings.NewReader(payload))
+		if len(test.signature) != 0 {
+			r.Header.Set(SignatureHeader, test.signature)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != test.want {
+			t.Errorf("signature [%v] status [%v]; want [%v]", test.signature, w.Code, test.want)
+		}
+	}
+}
+
+func Test_RequireSignatureTooLarge(t *testing.T) {
+	h := RequireSignature(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("handler called for an oversized delivery")
+	}), "new")
+
+	payload := `{"push": {}, "padding": "` + strings.Repeat("x", DefaultMaxBodyBytes) + `"}`
+	r := httptest.NewRequest("POST", "/hook", strings.NewReader(payload))
+	r.Header.Set(SignatureHeader, sign(payload, "new"))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status [%v]; want [%v]", w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
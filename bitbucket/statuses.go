@@ -0,0 +1,185 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The states of a build status.
+const (
+	StatusStateInProgress = "INPROGRESS"
+	StatusStateSuccessful = "SUCCESSFUL"
+	StatusStateFailed     = "FAILED"
+	StatusStateStopped    = "STOPPED"
+)
+
+// CommitStatus is the status of a build, or other check, run
+// against a commit.
+type CommitStatus struct {
+	// A key that uniquely identifies the build for the commit, such as
+	// the name of the CI job. Reporting a status with the same key
+	// replaces the previous status.
+	Key string `json:"key"`
+
+	// One of the StatusState constants.
+	State string `json:"state"`
+
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// A link to the build results.
+	URL string `json:"url"`
+
+	// The branch or tag the build ran against, if any.
+	Refname string `json:"refname,omitempty"`
+
+	// The commit the status was reported against. This is only
+	// included in webhook payloads.
+	Commit *CommitRef `json:"commit,omitempty"`
+
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+	Links     *Links    `json:"links,omitempty"`
+}
+
+// body converts the status to the JSON structure expected by
+// Bitbucket, omitting the read-only fields.
+func (s *CommitStatus) body() map[string]interface{} {
+	body := map[string]interface{}{
+		"key":   s.Key,
+		"state": s.State,
+		"url":   s.URL,
+	}
+	if len(s.Name) != 0 {
+		body["name"] = s.Name
+	}
+	if len(s.Description) != 0 {
+		body["description"] = s.Description
+	}
+	if len(s.Refname) != 0 {
+		body["refname"] = s.Refname
+	}
+	return body
+}
+
+// AggregateStatus is the overall status of a commit, combining all of
+// the build statuses reported against it.
+type AggregateStatus struct {
+	// The hash of the commit.
+	Commit string
+
+	// FAILED if any build failed, otherwise STOPPED if any build was
+	// stopped, otherwise INPROGRESS if any build is still running,
+	// otherwise SUCCESSFUL. Empty if no statuses were reported.
+	State string
+
+	Statuses []*CommitStatus
+}
+
+// Use the statuses resource to report the results of builds against the
+// commits of a repository. This resource uses the 2.0 API.
+//
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-commit-statuses/
+type StatusResource struct {
+	client *Client
+}
+
+// Gets the build statuses reported against a commit.
+func (r *StatusResource) List(ctx context.Context, owner, slug, revision string, opts *ListOptions) *Iterator[*CommitStatus] {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/statuses", owner, slug, revision)
+	return newIterator[*CommitStatus](ctx, r.client, path, nil, opts)
+}
+
+// Gets the build status with the given key.
+func (r *StatusResource) Find(ctx context.Context, owner, slug, revision, key string) (*CommitStatus, error) {
+	status := CommitStatus{}
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/statuses/build/%s", owner, slug, revision, key)
+
+	if err := r.client.do2(ctx, "GET", path, nil, nil, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// Reports a build status against a commit.
+func (r *StatusResource) Create(ctx context.Context, owner, slug, revision string, status *CommitStatus) (*CommitStatus, error) {
+	s := CommitStatus{}
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/statuses/build", owner, slug, revision)
+
+	if err := r.client.do2(ctx, "POST", path, nil, status.body(), &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Updates an existing build status, identified by its key.
+func (r *StatusResource) Update(ctx context.Context, owner, slug, revision string, status *CommitStatus) (*CommitStatus, error) {
+	s := CommitStatus{}
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/statuses/build/%s", owner, slug, revision, status.Key)
+
+	if err := r.client.do2(ctx, "PUT", path, nil, status.body(), &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// CreateUpdate will update the build status with the same key if it
+// exists, or create it otherwise.
+func (r *StatusResource) CreateUpdate(ctx context.Context, owner, slug, revision string, status *CommitStatus) (*CommitStatus, error) {
+	_, err := r.Find(ctx, owner, slug, revision, status.Key)
+	switch {
+	case err == nil:
+		return r.Update(ctx, owner, slug, revision, status)
+	case errors.Is(err, ErrNotFound):
+		return r.Create(ctx, owner, slug, revision, status)
+	default:
+		return nil, err
+	}
+}
+
+// Gets the aggregated build status of the head commit of
+// a pull request.
+func (r *StatusResource) PullRequestStatus(ctx context.Context, owner, slug string, id int) (*AggregateStatus, error) {
+	pr, err := r.client.PullRequests.Find(ctx, owner, slug, id)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Source == nil || pr.Source.Commit == nil {
+		return nil, fmt.Errorf("pull request %v has no source commit", id)
+	}
+
+	statuses, err := r.List(ctx, owner, slug, pr.Source.Commit.Hash, nil).All()
+	if err != nil {
+		return nil, err
+	}
+
+	return aggregateStatus(pr.Source.Commit.Hash, statuses), nil
+}
+
+// aggregateStatus combines the build statuses of a commit.
+func aggregateStatus(commit string, statuses []*CommitStatus) *AggregateStatus {
+	agg := &AggregateStatus{
+		Commit:   commit,
+		Statuses: statuses,
+	}
+
+	// the states in order of precedence
+	precedence := map[string]int{
+		StatusStateSuccessful: 1,
+		StatusStateInProgress: 2,
+		StatusStateStopped:    3,
+		StatusStateFailed:     4,
+	}
+	for _, s := range statuses {
+		if precedence[s.State] > precedence[agg.State] {
+			agg.State = s.State
+		}
+	}
+
+	return agg
+}
@@ -0,0 +1,90 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func Test_Statuses(t *testing.T) {
+	var requests []string
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /2.0/repositories/marcus/project-x/commit/620ade18607a/statuses/build/ci-unit":
+			w.WriteHeader(http.StatusNotFound)
+		case "POST /2.0/repositories/marcus/project-x/commit/620ade18607a/statuses/build":
+			body := decodeBody(t, r)
+			if body["key"] != "ci-unit" || body["state"] != StatusStateInProgress || body["url"] != "https://ci.example.com/1" {
+				t.Errorf("status body [%v]", body)
+			}
+			if _, ok := body["created_on"]; ok {
+				t.Errorf("expected read-only fields to be omitted; got %v", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"key": "ci-unit", "state": "INPROGRESS"}`))
+		default:
+			t.Errorf("unexpected request [%v %v]", r.Method, r.URL.Path)
+		}
+	})
+
+	status, err := c.Statuses.CreateUpdate(context.Background(), "marcus", "project-x", "620ade18607a", &CommitStatus{
+		Key:   "ci-unit",
+		State: StatusStateInProgress,
+		Name:  "Unit tests",
+		URL:   "https://ci.example.com/1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Key != "ci-unit" || len(requests) != 2 {
+		t.Errorf("status [%v] after requests %v", status.Key, requests)
+	}
+}
+
+func Test_StatusesPullRequest(t *testing.T) {
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2.0/repositories/marcus/project-x/pullrequests/7":
+			w.Write([]byte(`{"id": 7, "source": {"branch": {"name": "feature"}, "commit": {"hash": "620ade18607a"}}}`))
+		case "/2.0/repositories/marcus/project-x/commit/620ade18607a/statuses":
+			w.Write([]byte(`{"values": [
+				{"key": "ci-unit", "state": "SUCCESSFUL"},
+				{"key": "ci-lint", "state": "INPROGRESS"}
+			]}`))
+		default:
+			t.Errorf("unexpected request [%v %v]", r.Method, r.URL.Path)
+		}
+	})
+
+	agg, err := c.Statuses.PullRequestStatus(context.Background(), "marcus", "project-x", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agg.Commit != "620ade18607a" || agg.State != StatusStateInProgress || len(agg.Statuses) != 2 {
+		t.Errorf("aggregate status [%v] [%v] [%v]", agg.Commit, agg.State, len(agg.Statuses))
+	}
+}
+
+func Test_AggregateStatus(t *testing.T) {
+	tests := []struct {
+		states []string
+		want   string
+	}{
+		{nil, ""},
+		{[]string{StatusStateSuccessful, StatusStateSuccessful}, StatusStateSuccessful},
+		{[]string{StatusStateSuccessful, StatusStateInProgress}, StatusStateInProgress},
+		{[]string{StatusStateInProgress, StatusStateStopped}, StatusStateStopped},
+		{[]string{StatusStateFailed, StatusStateStopped, StatusStateSuccessful}, StatusStateFailed},
+	}
+
+	for _, test := range tests {
+		statuses := []*CommitStatus{}
+		for _, state := range test.states {
+			statuses = append(statuses, &CommitStatus{State: state})
+		}
+		if got := aggregateStatus("abc", statuses).State; got != test.want {
+			t.Errorf("aggregate of %v [%v]; want [%v]", test.states, got, test.want)
+		}
+	}
+}
@@ -1,5 +1,9 @@
 package bitbucket
 
+import (
+	"context"
+)
+
 const (
 	TeamRoleAdmin = "admin"
 	TeamRoleCollab = "collaborator"
@@ -26,7 +30,7 @@ type Team struct {
 }
 
 // Gets the groups with account privileges defined for a team account.
-func (r *TeamResource) List() ([]*Team, error) {
+func (r *TeamResource) List(ctx context.Context) ([]*Team, error) {
 	
 	// we'll get the data in a key/value struct
 	data := struct {
@@ -36,7 +40,7 @@ func (r *TeamResource) List() ([]*Team, error) {
 	data.Teams = map[string]string{}
 	teams := []*Team{}
 
-	if err := r.client.do("GET", "/user/privileges", nil, nil, &data); err != nil {
+	if err := r.client.do(ctx, "GET", "/user/privileges", nil, nil, &data); err != nil {
 		return nil, err
 	}
 
@@ -1,12 +1,13 @@
 package bitbucket
 
 import (
+	"context"
 	"testing"
 )
 
 func Test_Teams(t *testing.T) {
 
-	teams, err := client.Teams.List()
+	teams, err := client.Teams.List(context.Background())
 	if err != nil {
 		t.Error(err)
 		return
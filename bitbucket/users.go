@@ -1,6 +1,7 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -19,6 +20,13 @@ type User struct {
 	ResourceURI string `json:"resource_uri"`
 	IsTeam      bool   `json:"is_team"` // Indicates if this is a Team account.
 
+	// Fields returned by the 2.0 API, which identifies accounts
+	// by UUID and account ID rather than username.
+	Type      string `json:"type,omitempty"`
+	UUID      string `json:"uuid,omitempty"`
+	AccountID string `json:"account_id,omitempty"`
+	Nickname  string `json:"nickname,omitempty"`
+	Links     *Links `json:"links,omitempty"`
 }
 
 // Use the /user endpoints to gets information related to a user
@@ -30,9 +38,9 @@ type UserResource struct {
 
 // Current - Gets the basic information associated with an account and a list
 // of all its repositories both public and private.
-func (r *UserResource) Current() (*Account, error) {
+func (r *UserResource) Current(ctx context.Context) (*Account, error) {
 	user := Account{}
-	if err := r.client.do("GET", "/user", nil, nil, &user); err != nil {
+	if err := r.client.do(ctx, "GET", "/user", nil, nil, &user); err != nil {
 		return nil, err
 	}
 
@@ -41,11 +49,11 @@ func (r *UserResource) Current() (*Account, error) {
 
 // Find - Gets the basic information associated with the specified user
 // account.
-func (r *UserResource) Find(username string) (*Account, error) {
+func (r *UserResource) Find(ctx context.Context, username string) (*Account, error) {
 	user := Account{}
 	path := fmt.Sprintf("/users/%s", username)
 
-	if err := r.client.do("GET", path, nil, nil, &user); err != nil {
+	if err := r.client.do(ctx, "GET", path, nil, nil, &user); err != nil {
 		return nil, err
 	}
 
@@ -55,7 +63,7 @@ func (r *UserResource) Find(username string) (*Account, error) {
 /* TODO
 // Update the basic information associated with an account.
 // It operates on the currently authenticated user.
-func (r *UserResource) Update(user *User) (*User, error) {
+func (r *UserResource) Update(ctx context.Context, user *User) (*User, error) {
 	return nil, nil
 }
 */
@@ -1,19 +1,20 @@
 package bitbucket
 
 import (
+	"context"
 	"testing"
 )
 
 func Test_Users(t *testing.T) {
 
 	// FIND the currently authenticated user
-	curr, err := client.Users.Current()
+	curr, err := client.Users.Current(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
 
 	// Find the user by Id
-	user, err := client.Users.Find(curr.User.Username)
+	user, err := client.Users.Find(context.Background(), curr.User.Username)
 	if err != nil {
 		t.Error(err)
 	}
@@ -28,7 +29,7 @@ func Test_Users(t *testing.T) {
 func Test_UsersGuest(t *testing.T) {
 
 	// FIND the currently authenticated user
-	user, err := Guest.Users.Find(testUser)
+	user, err := Guest.Users.Find(context.Background(), testUser)
 	if err != nil {
 		t.Error(err)
 	}
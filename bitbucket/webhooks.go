@@ -0,0 +1,211 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// The events a webhook can subscribe to.
+const (
+	EventRepoPush                 = "repo:push"
+	EventRepoFork                 = "repo:fork"
+	EventRepoUpdated              = "repo:updated"
+	EventRepoCommitCommentCreated = "repo:commit_comment_created"
+	EventRepoCommitStatusCreated  = "repo:commit_status_created"
+	EventRepoCommitStatusUpdated  = "repo:commit_status_updated"
+
+	EventPullRequestCreated               = "pullrequest:created"
+	EventPullRequestUpdated               = "pullrequest:updated"
+	EventPullRequestApproved              = "pullrequest:approved"
+	EventPullRequestUnapproved            = "pullrequest:unapproved"
+	EventPullRequestChangesRequestCreated = "pullrequest:changes_request_created"
+	EventPullRequestChangesRequestRemoved = "pullrequest:changes_request_removed"
+	EventPullRequestFulfilled             = "pullrequest:fulfilled"
+	EventPullRequestRejected              = "pullrequest:rejected"
+	EventPullRequestCommentCreated        = "pullrequest:comment_created"
+	EventPullRequestCommentUpdated        = "pullrequest:comment_updated"
+	EventPullRequestCommentDeleted        = "pullrequest:comment_deleted"
+	EventPullRequestCommentResolved       = "pullrequest:comment_resolved"
+	EventPullRequestCommentReopened       = "pullrequest:comment_reopened"
+
+	EventIssueCreated        = "issue:created"
+	EventIssueUpdated        = "issue:updated"
+	EventIssueCommentCreated = "issue:comment_created"
+)
+
+// Webhook is a URL that Bitbucket POSTs to when the events it subscribes
+// to occur in a repository, or in any repository of a workspace.
+type Webhook struct {
+	UUID        string `json:"uuid"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+	Active      bool   `json:"active"`
+
+	// The events that trigger the webhook, from the Event constants.
+	Events []string `json:"events"`
+
+	// The secret Bitbucket signs each request with, in the
+	// X-Hub-Signature header. Bitbucket never returns the secret, only
+	// whether one is set.
+	Secret    string `json:"secret,omitempty"`
+	SecretSet bool   `json:"secret_set"`
+
+	// Either "repository" or "workspace".
+	SubjectType string `json:"subject_type"`
+
+	CreatedAt time.Time `json:"created_at"`
+	Links     *Links    `json:"links,omitempty"`
+}
+
+// body converts the webhook to the JSON structure expected by
+// Bitbucket, omitting the read-only fields.
+func (h *Webhook) body() map[string]interface{} {
+	events := h.Events
+	if events == nil {
+		events = []string{}
+	}
+	body := map[string]interface{}{
+		"url":         h.URL,
+		"description": h.Description,
+		"active":      h.Active,
+		"events":      events,
+	}
+	if len(h.Secret) != 0 {
+		body["secret"] = h.Secret
+	}
+	return body
+}
+
+// Use the webhooks resource to manage the webhooks of a repository or a
+// workspace. This resource uses the 2.0 API, and replaces the legacy
+// services managed by the BrokerResource.
+//
+// Every method takes the owner and slug of a repository. If the slug is
+// empty, the owner is taken to be a workspace, and the method manages
+// the webhooks of the workspace instead.
+//
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-repositories/#api-repositories-workspace-repo-slug-hooks-get
+type WebhookResource struct {
+	client *Client
+}
+
+// hooksPath gets the path of the webhooks of a repository, or of
+// a workspace if the slug is empty.
+func hooksPath(owner, slug string) string {
+	if len(slug) == 0 {
+		return fmt.Sprintf("/workspaces/%s/hooks", owner)
+	}
+	return fmt.Sprintf("/repositories/%s/%s/hooks", owner, slug)
+}
+
+// Gets the webhooks of a repository or workspace.
+func (r *WebhookResource) List(ctx context.Context, owner, slug string, opts *ListOptions) *Iterator[*Webhook] {
+	return newIterator[*Webhook](ctx, r.client, hooksPath(owner, slug), nil, opts)
+}
+
+// Gets the webhook with the given UUID.
+func (r *WebhookResource) Find(ctx context.Context, owner, slug, uuid string) (*Webhook, error) {
+	hook := Webhook{}
+	path := fmt.Sprintf("%s/%s", hooksPath(owner, slug), uuid)
+
+	if err := r.client.do2(ctx, "GET", path, nil, nil, &hook); err != nil {
+		return nil, err
+	}
+
+	return &hook, nil
+}
+
+// Gets the webhook that POSTs to the given URL.
+func (r *WebhookResource) FindUrl(ctx context.Context, owner, slug, link string) (*Webhook, error) {
+	it := r.List(ctx, owner, slug, nil)
+	for it.Next() {
+		if hook := it.Value(); hook.URL == link {
+			return hook, nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrNotFound
+}
+
+// Creates a webhook.
+func (r *WebhookResource) Create(ctx context.Context, owner, slug string, hook *Webhook) (*Webhook, error) {
+	h := Webhook{}
+
+	if err := r.client.do2(ctx, "POST", hooksPath(owner, slug), nil, hook.body(), &h); err != nil {
+		return nil, err
+	}
+
+	return &h, nil
+}
+
+// Updates an existing webhook, identified by its UUID. The secret is
+// left unchanged if empty.
+func (r *WebhookResource) Update(ctx context.Context, owner, slug string, hook *Webhook) (*Webhook, error) {
+	h := Webhook{}
+	path := fmt.Sprintf("%s/%s", hooksPath(owner, slug), hook.UUID)
+
+	if err := r.client.do2(ctx, "PUT", path, nil, hook.body(), &h); err != nil {
+		return nil, err
+	}
+
+	return &h, nil
+}
+
+// CreateUpdate will create the webhook if no webhook POSTs to its URL,
+// or otherwise update the existing webhook if it is different.
+//
+// Since Bitbucket never returns the secret, a changed secret is only
+// detected if the existing webhook has no secret at all.
+func (r *WebhookResource) CreateUpdate(ctx context.Context, owner, slug string, hook *Webhook) (*Webhook, error) {
+	found, err := r.FindUrl(ctx, owner, slug, hook.URL)
+	if errors.Is(err, ErrNotFound) {
+		return r.Create(ctx, owner, slug, hook)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// if the webhook is different we should update
+	if found.Description != hook.Description || found.Active != hook.Active ||
+		!sameEvents(found.Events, hook.Events) || (len(hook.Secret) != 0 && !found.SecretSet) {
+		h := *hook
+		h.UUID = found.UUID
+		return r.Update(ctx, owner, slug, &h)
+	}
+
+	// otherwise we should just return the webhook, since there
+	// is nothing to update
+	return found, nil
+}
+
+// Deletes the webhook with the given UUID.
+func (r *WebhookResource) Delete(ctx context.Context, owner, slug, uuid string) error {
+	path := fmt.Sprintf("%s/%s", hooksPath(owner, slug), uuid)
+	return r.client.do2(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// Deletes the webhook that POSTs to the given URL.
+func (r *WebhookResource) DeleteUrl(ctx context.Context, owner, slug, link string) error {
+	hook, err := r.FindUrl(ctx, owner, slug, link)
+	if err != nil {
+		return err
+	}
+
+	return r.Delete(ctx, owner, slug, hook.UUID)
+}
+
+// sameEvents reports whether the lists hold the same events, in
+// any order.
+func sameEvents(a, b []string) bool {
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return sameKeys(a, b)
+}
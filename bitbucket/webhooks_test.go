@@ -0,0 +1,124 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func Test_Webhooks(t *testing.T) {
+	var requests []string
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /2.0/repositories/marcus/project-x/hooks", "GET /2.0/workspaces/marcus/hooks":
+			w.Write([]byte(sampleWebhooks))
+		case "POST /2.0/workspaces/marcus/hooks":
+			body := decodeBody(t, r)
+			if body["url"] != "https://ci.example.com/hook" || body["active"] != true || body["secret"] != "s3cret" {
+				t.Errorf("webhook body [%v]", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"uuid": "{h2}", "url": "https://ci.example.com/hook", "secret_set": true}`))
+		case "PUT /2.0/repositories/marcus/project-x/hooks/{h1}":
+			body := decodeBody(t, r)
+			if events, ok := body["events"].([]interface{}); !ok || len(events) != 3 {
+				t.Errorf("webhook events %v", events)
+			}
+			if _, ok := body["secret"]; ok {
+				t.Errorf("expected an empty secret to be omitted; got %v", body)
+			}
+			w.Write([]byte(`{"uuid": "{h1}"}`))
+		case "DELETE /2.0/repositories/marcus/project-x/hooks/{h1}":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request [%v %v]", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+
+	hook := &Webhook{
+		URL:         "https://example.com/hook",
+		Description: "Deploys",
+		Active:      true,
+		Events:      []string{EventPullRequestFulfilled, EventRepoPush},
+	}
+
+	// CREATE UPDATE with nothing to change
+	found, err := c.Webhooks.CreateUpdate(ctx, "marcus", "project-x", hook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.UUID != "{h1}" || !found.SecretSet || found.CreatedAt.IsZero() {
+		t.Errorf("webhook [%v] secret set [%v] created at [%v]", found.UUID, found.SecretSet, found.CreatedAt)
+	}
+	if len(requests) != 1 {
+		t.Errorf("expected no update; got requests %v", requests)
+	}
+
+	// CREATE UPDATE with a new event
+	hook.Events = append(hook.Events, EventPullRequestRejected)
+	if _, err := c.Webhooks.CreateUpdate(ctx, "marcus", "project-x", hook); err != nil {
+		t.Fatal(err)
+	}
+
+	// CREATE UPDATE a workspace hook that doesn't exist
+	created, err := c.Webhooks.CreateUpdate(ctx, "marcus", "", &Webhook{
+		URL:    "https://ci.example.com/hook",
+		Active: true,
+		Secret: "s3cret",
+		Events: []string{EventRepoPush},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.UUID != "{h2}" {
+		t.Errorf("webhook [%v]; want [%v]", created.UUID, "{h2}")
+	}
+
+	// DELETE by URL
+	if err := c.Webhooks.DeleteUrl(ctx, "marcus", "project-x", "https://example.com/hook"); err != nil {
+		t.Error(err)
+	}
+
+	if len(requests) != 7 {
+		t.Errorf("requests %v", requests)
+	}
+}
+
+var sampleWebhooks = `
+{
+    "pagelen": 10,
+    "values": [
+        {
+            "type": "webhook_subscription",
+            "uuid": "{h1}",
+            "url": "https://example.com/hook",
+            "description": "Deploys",
+            "subject_type": "repository",
+            "active": true,
+            "secret_set": true,
+            "created_at": "2024-03-01T12:00:00.000000+00:00",
+            "events": ["repo:push", "pullrequest:fulfilled"]
+        }
+    ]
+}
+`
+
+func Test_WebhooksCreateUpdateListError(t *testing.T) {
+	c, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("unexpected request [%v %v]; want no webhook created", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := c.Webhooks.CreateUpdate(context.Background(), "marcus", "project-x", &Webhook{
+		URL:    "https://example.com/hook",
+		Active: true,
+		Events: []string{EventRepoPush},
+	})
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("error [%v]; want a 500 *APIError", err)
+	}
+}
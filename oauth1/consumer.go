@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -215,12 +216,16 @@ func (c *Consumer) SignParams(req *http.Request, token Token, params map[string]
 // -----------------------------------------------------------------------------
 // Private Helper Functions
 
-// Nonce generator, seeded with current time
+// Nonce generator, seeded with current time. A rand.Rand is not safe
+// for concurrent use, so access is guarded by nonceMutex.
 var nonceGenerator = rand.New(rand.NewSource(time.Now().Unix()))
+var nonceMutex sync.Mutex
 
 // Nonce generates a random string. Nonce's are uniquely generated
 // for each request.
 func nonce() string {
+	nonceMutex.Lock()
+	defer nonceMutex.Unlock()
 	return strconv.FormatInt(nonceGenerator.Int63(), 10)
 }
 